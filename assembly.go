@@ -1,9 +1,13 @@
 package modz
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"log/slog"
 	"sync"
 	"sync/atomic"
+	"time"
 )
 
 // Assembly represents a specific composition of [Module], defining how each is
@@ -24,18 +28,22 @@ import (
 // successfully. Attempting to access data before Build() completes or after Build() fails
 // will return an error.
 //
-// This design keeps the framework focused on construction and wiring, leaving the
-// application's runtime behavior and lifecycle management in the hands of the user.
+// Once built, the Assembly can start and stop the modules implementing [Starter] and
+// [Stopper], in dependency order. The application's runtime behavior otherwise remains in
+// the hands of the user.
 type Assembly interface {
 	DataReader
 
 	// Build orchestrates the complete module assembly process.
 	//
 	// This method orchestrates each [Module]'s lifecycle phases to construct
-	// the dependency graph and wire up all module dependencies.
+	// the dependency graph and wire up all module dependencies. Modules are configured
+	// one at a time unless the Assembly was created with [WithParallelism].
 	//
 	// Returns an error if any phase fails, such as circular dependencies,
-	// missing required [Data], or module configuration errors. On success,
+	// missing required [Data], or module configuration errors. Circular dependencies are
+	// reported as [CycleError] naming the full chain of modules and keys, and keys that no
+	// module produces are reported as [MissingProducerError]. On success,
 	// the Assembly has completed the construction and wiring phases and
 	// is ready for runtime use.
 	//
@@ -46,6 +54,59 @@ type Assembly interface {
 	// Build() fails, data access methods will return an error.
 	Build() error
 
+	// BuildContext is like Build() but runs the module lifecycle under the given context.
+	//
+	// The context is exposed to modules through [Binder].Context(), bounded by the timeouts
+	// configured with [WithBuildTimeout] and [WithModuleTimeout]. When the context is done,
+	// no further modules are configured and BuildContext returns a [ConfigurationError]
+	// identifying the module that was still configuring, wrapping the context's error.
+	// A module that ignores the context is abandoned: BuildContext returns without waiting
+	// for its Configure() call to finish.
+	//
+	// Build() is equivalent to BuildContext(context.Background()).
+	BuildContext(ctx context.Context) error
+
+	// Graph returns a snapshot of the dependency graph formed by the installed modules and the
+	// [Data] they produce and consume. The snapshot can be exported as Graphviz DOT, Mermaid or
+	// JSON.
+	//
+	// Graph may be called before or after Build(). Before Build() it only includes the modules
+	// passed to [NewAssembly]; modules installed during configuration appear once Build() has
+	// configured their parents.
+	Graph() *Graph
+
+	// DataEntries lists the values held by the Assembly, sorted by key signature, with the
+	// modules producing and consuming each of them. Values inherited from a parent assembly
+	// (see [WithParent]) are listed by the parent.
+	//
+	// DataEntries may be called before or after Build(); during or after a failed Build() it
+	// only lists the values produced so far. It does not compute values provided lazily with
+	// [Data].Provide().
+	DataEntries() []DataEntry
+
+	// BuildReport returns a report of the time spent configuring each module during Build(),
+	// and of the [DataKey] whose value made each module ready. It returns nil before Build()
+	// is called. See also [WithBuildObserver].
+	BuildReport() *BuildReport
+
+	// Start calls Start() on every module implementing [Starter], in the order in which Build()
+	// configured the modules, so that a module starts after the modules it consumes [Data] from.
+	//
+	// If a module fails to start, or ctx is done before every module is started, the modules
+	// already started are stopped in reverse order, as by Stop(), and Start returns a
+	// [ConfigurationError] for the module that failed, joined with any error from stopping the
+	// others.
+	//
+	// Start can only be called once, after Build() has completed successfully.
+	Start(ctx context.Context) error
+
+	// Stop calls Stop() on every module implementing [Stopper], in the reverse of the order
+	// in which Start() reached them. Every module is stopped even if stopping another one
+	// fails; Stop returns a [ConfigurationError] for each module that failed, joined together.
+	//
+	// Stop can only be called after Start() has completed successfully, and only once.
+	Stop(ctx context.Context) error
+
 	// sealAssembly is an unexported marker method used to seal the interface.
 	sealAssembly()
 }
@@ -55,44 +116,93 @@ type Assembly interface {
 // The built field tracks whether Build has already been called, enforcing once-only semantics.
 // The buildCompleted field tracks whether Build has completed successfully.
 type assembly struct {
-	mu             sync.RWMutex // protects all fields below except built and buildCompleted
+	mu             sync.RWMutex // protects all fields below up to built
 	bindings       map[moduleSignature]*binder
 	registry       *dataRegistry
 	data           map[DataKey]any
 	waiters        map[DataKey][]*binder
-	producers      map[DataKey]*binder // tracks which module produces each data key
+	producers      map[DataKey]*binder   // tracks which module produces each data key
+	contributors   map[DataKey][]*binder // tracks which modules contribute to each collection key
+	contributions  map[DataKey][]contribution
+	inherited      map[DataKey]struct{}  // keys read from the parent assembly
+	defaulted      map[DataKey]struct{}  // keys satisfied with their default value
+	decorators     map[DataKey][]*binder // tracks which modules decorate each key, sorted by signature
+	decorations    map[DataKey]*decoration
 	ready          binderQueue
+	options        assemblyOptions
+	wake           chan struct{} // signaled whenever a binder is added to the ready queue
+	order          []*binder     // modules in the order their configuration started
+	buildStarted   time.Time
+	buildFinished  time.Time
 	built          atomic.Bool // true after Build has been called
 	buildCompleted atomic.Bool // true after Build has completed successfully
+	lifecycleMu    sync.Mutex  // serializes Start and Stop; protects lifecycle
+	lifecycle      lifecycle
 }
 
 // Ensure that *assembly implements Assembly.
 var _ Assembly = (*assembly)(nil)
 
 func (a *assembly) Build() error {
+	return a.BuildContext(context.Background())
+}
+
+func (a *assembly) BuildContext(ctx context.Context) error {
 	if !a.built.CompareAndSwap(false, true) {
-		return fmt.Errorf("Build: can only be called once")
+		return newBuildStateError(ErrAlreadyBuilt, "Build", "can only be called once")
 	}
-	for {
-		a.mu.Lock()
-		b := a.ready.Pop()
-		a.mu.Unlock()
-		if b == nil {
-			break
-		}
-		if err := b.configureModule(); err != nil {
-			return err
-		}
+	if a.options.buildTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, a.options.buildTimeout)
+		defer cancel()
+	}
+	a.mu.Lock()
+	a.buildStarted = time.Now()
+	a.mu.Unlock()
+	observer := a.options.observer
+	if observer != nil {
+		ctx = observer.BuildStarted(ctx)
 	}
+	err := a.build(ctx)
+	a.mu.Lock()
+	a.buildFinished = time.Now()
+	duration := a.buildFinished.Sub(a.buildStarted)
+	configured := len(a.order)
+	a.mu.Unlock()
+	if err != nil {
+		a.log(ctx, slog.LevelError, "build failed", slog.Duration("duration", duration), slog.Any("error", err))
+	} else {
+		a.log(ctx, slog.LevelInfo, "build completed", slog.Duration("duration", duration), slog.Int("modules", configured))
+	}
+	if observer != nil {
+		observer.BuildFinished(ctx, a.BuildReport(), err)
+	}
+	return err
+}
+
+// build configures the modules and reports the outcome of Build.
+func (a *assembly) build(ctx context.Context) error {
+	errs := a.configureModules(ctx)
 	a.mu.RLock()
 	defer a.mu.RUnlock()
-	if len(a.waiters) > 0 {
-		// Collect missing keys for error message
-		var missingKeys []string
-		for k := range a.waiters {
-			missingKeys = append(missingKeys, fmt.Sprintf("%v", k))
+	if !a.options.aggregateErrors {
+		if len(errs) > 0 {
+			return errs[0]
+		}
+		if len(a.waitingModules()) > 0 {
+			waitErrs, _ := a.diagnoseWaiters()
+			return fmt.Errorf("build incomplete: %w", errors.Join(waitErrs...))
+		}
+	} else {
+		var skipped []string
+		if len(a.waitingModules()) > 0 && ctx.Err() == nil {
+			var waitErrs []error
+			waitErrs, skipped = a.diagnoseWaiters()
+			errs = append(errs, waitErrs...)
+		}
+		if len(errs) > 0 {
+			return &AssemblyError{Errors: errs, Skipped: skipped}
 		}
-		return fmt.Errorf("build incomplete: some modules are still waiting for data keys: %v", missingKeys)
 	}
 	a.buildCompleted.Store(true)
 	return nil
@@ -100,13 +210,71 @@ func (a *assembly) Build() error {
 
 func (*assembly) sealAssembly() {}
 
+// configureModules configures ready modules until none is left or a module fails, and
+// returns the errors encountered.
+//
+// Up to options.parallelism modules are configured concurrently, each in its own goroutine.
+// Modules made ready while others are still being configured are started as soon as a
+// worker is free. After the first failure no further modules are started, unless errors are
+// being aggregated, in which case every module that becomes ready is still configured. Once
+// ctx is done no further modules are started. The modules already running are always waited for.
+func (a *assembly) configureModules(ctx context.Context) []error {
+	results := make(chan error)
+	inFlight := 0
+	var errs []error
+	stopped := false
+	for {
+		for !stopped && inFlight < a.options.parallelism {
+			if err := ctx.Err(); err != nil {
+				errs = append(errs, fmt.Errorf("Build: %w", err))
+				stopped = true
+				break
+			}
+			a.mu.Lock()
+			b := a.ready.Pop()
+			a.mu.Unlock()
+			if b == nil {
+				break
+			}
+			inFlight++
+			go func() {
+				// The result is sent even if the goroutine exits through runtime.Goexit,
+				// so that Build never waits for a module that will not report back.
+				err := newNotReturnedError(b.moduleSignature.String())
+				defer func() {
+					if err != nil {
+						b.failed.Store(true)
+					}
+					results <- err
+				}()
+				err = a.runModule(ctx, b)
+			}()
+		}
+		if inFlight == 0 {
+			if !stopped && a.settle() {
+				continue
+			}
+			return errs
+		}
+		select {
+		case err := <-results:
+			inFlight--
+			if err != nil && !(stopped && !a.options.aggregateErrors) {
+				errs = append(errs, err)
+				stopped = stopped || !a.options.aggregateErrors
+			}
+		case <-a.wake:
+		}
+	}
+}
+
 // getData retrieves a value stored under the specified DataKey.
 //
 // This method can only be called after Build() has completed successfully.
 // Returns an error if called before Build() completes or if the DataKey is not found.
 func (a *assembly) getData(key DataKey) (any, error) {
 	if !a.buildCompleted.Load() {
-		return nil, fmt.Errorf("getData: can only be called after Build has completed successfully")
+		return nil, newBuildStateError(ErrNotBuilt, "getData", "can only be called after Build has completed successfully")
 	}
 	return a.getDataValue(key)
 }
@@ -114,42 +282,110 @@ func (a *assembly) getData(key DataKey) (any, error) {
 // install adds a module into the assembly. Returns an error if the module cannot be installed.
 func (a *assembly) install(m Module, parent *binder) error {
 	if m == nil {
-		return newInstallError("unknown", "cannot add nil module")
+		return newInstallError(ErrInvalidArgument, "unknown", "cannot add nil module")
 	}
 	sig := newModuleSignature(m)
 	a.mu.Lock()
 	defer a.mu.Unlock()
 
+	// Check if this module is a singleton
+	_, singleton := m.(interface{ singleton() })
 	// Check if this module is already installed
 	if _, exists := a.bindings[sig]; exists {
-		// Check if this module is a singleton
-		_, singleton := m.(interface{ singleton() })
 		// If it's a singleton, silently ignore (no-op)
 		if singleton {
+			a.log(context.Background(), slog.LevelDebug, "singleton module already installed", slog.String("module", sig.String()))
 			return nil
 		}
 		// If it's not a singleton, return an error
-		return newInstallError(sig.String(), "already added")
+		return newInstallError(ErrDuplicateModule, sig.String(), "already added")
+	}
+	// A singleton installed in a parent assembly is shared with its children
+	if singleton && a.parentHasModule(sig) {
+		a.log(context.Background(), slog.LevelDebug, "singleton module installed in parent assembly", slog.String("module", sig.String()))
+		return nil
+	}
+	installed, err := a.overrideModule(m, sig)
+	if err != nil {
+		return err
 	}
-	b := newBinder(a, m, parent, sig)
+	b := newBinder(a, installed, parent, newModuleSignature(installed))
 	if err := b.discoverModule(); err != nil {
 		return err
 	}
+	a.log(context.Background(), slog.LevelDebug, "module discovered", moduleAttr(b),
+		keysAttr("produces", b.produces), keysAttr("consumes", b.consumes), keysAttr("optional", b.optional))
 
+	// Validate all declared keys before registering anything, so that a failed install
+	// leaves the assembly unchanged.
 	for k := range b.produces {
 		if err := a.registry.Validate(k); err != nil {
 			return err
 		}
+		if _, inherited := a.inherited[k]; inherited {
+			return newDataOperationError(ErrAlreadySet, k, fmt.Sprintf("module '%s' cannot produce a key already read from the parent assembly", sig))
+		}
+		if _, defaulted := a.defaulted[k]; defaulted {
+			return newDataOperationError(ErrAlreadySet, k, fmt.Sprintf("module '%s' cannot produce a key already set to its default value", sig))
+		}
+		if _, ok := k.(collectionKey); ok {
+			if _, assembled := a.data[k]; assembled {
+				return newDataOperationError(ErrAlreadySet, k, fmt.Sprintf("module '%s' cannot contribute to a collection that has already been assembled", sig))
+			}
+			continue
+		}
 		if existingProducer, exists := a.producers[k]; exists {
-			return fmt.Errorf("duplicate producer for data key '%s': modules '%s' and '%s' both declare they produce it", k, existingProducer.moduleSignature, sig)
+			return newDuplicateProducerError(k, existingProducer.moduleSignature.String(), sig.String())
 		}
-		a.producers[k] = b
 	}
-
-	for k := range b.consumes {
+	for k := range b.decorates {
+		if _, ok := k.(collectionKey); ok {
+			return newDataOperationError(ErrInvalidArgument, k, fmt.Sprintf("module '%s' cannot decorate a collection", sig))
+		}
+		if _, available := a.data[k]; available {
+			return newDataOperationError(ErrAlreadySet, k, fmt.Sprintf("module '%s' cannot decorate a key whose value is already available", sig))
+		}
+	}
+	for k := range b.waiting {
+		if _, required := b.requires[k]; required {
+			// Names a module, not a Data key.
+			continue
+		}
 		if err := a.registry.Validate(k); err != nil {
 			return err
 		}
+	}
+
+	for k := range b.produces {
+		if _, ok := k.(collectionKey); ok {
+			a.contributors[k] = append(a.contributors[k], b)
+		} else {
+			a.producers[k] = b
+		}
+	}
+	for k := range b.decorates {
+		a.addDecorator(k, b)
+	}
+	a.bindings[sig] = b
+	attrs := []slog.Attr{moduleAttr(b)}
+	if parent != nil {
+		attrs = append(attrs, slog.String("parent", parent.moduleSignature.String()))
+	}
+	if installed != m {
+		attrs = append(attrs, slog.String("replaces", sig.String()))
+	}
+	a.log(context.Background(), slog.LevelDebug, "module installed", attrs...)
+	if a.isSeeded(b) {
+		// Everything the module produces is already available: skip its configuration.
+		clear(b.waiting)
+		b.configured.Store(true)
+		return nil
+	}
+	for k := range b.waiting {
+		if _, decorates := b.decorates[k]; decorates {
+			// Resolved when the value is passed to the decorator, see advanceDecorations.
+			continue
+		}
 		if _, present := a.data[k]; !present {
 			a.waiters[k] = append(a.waiters[k], b)
 		} else {
@@ -157,9 +393,8 @@ func (a *assembly) install(m Module, parent *binder) error {
 		}
 	}
 	if b.isReady() {
-		a.ready.Push(b)
+		a.schedule(b)
 	}
-	a.bindings[sig] = b
 	return nil
 }
 
@@ -167,13 +402,21 @@ func (a *assembly) install(m Module, parent *binder) error {
 // This is used internally by the binder.
 func (a *assembly) getDataValue(key DataKey) (any, error) {
 	if key == nil {
-		return nil, newDataOperationError(nil, "cannot get data with nil key")
+		return nil, newDataOperationError(ErrInvalidArgument, nil, "cannot get data with nil key")
 	}
 	a.mu.RLock()
 	val, ok := a.data[key]
+	inherit := !ok && a.options.parent != nil && len(a.producersOf(key)) == 0
 	a.mu.RUnlock()
+	if inherit {
+		return a.options.parent.getDataValue(key)
+	}
 	if !ok {
-		return nil, newDataOperationError(key, "no value found")
+		return nil, newDataOperationError(ErrNotProduced, key, "no value found")
+	}
+	if lv, ok := val.(*lazyValue); ok {
+		// Computed outside the lock, since the constructor may take time.
+		return lv.get()
 	}
 	return val, nil
 }
@@ -182,25 +425,302 @@ func (a *assembly) getDataValue(key DataKey) (any, error) {
 // This is used internally by the binder.
 func (a *assembly) putDataValue(key DataKey, value any) error {
 	if key == nil {
-		return newDataOperationError(nil, "cannot put data with nil key")
+		return newDataOperationError(ErrInvalidArgument, nil, "cannot put data with nil key")
+	}
+	if vk, ok := key.(validatedKey); ok {
+		if err := vk.validate(value); err != nil {
+			return err
+		}
 	}
 	a.mu.Lock()
 	defer a.mu.Unlock()
+	if _, seeded := a.options.seededData[key]; seeded {
+		// The value set with WithData takes precedence over the producer's.
+		return nil
+	}
 	if _, exists := a.data[key]; exists {
-		return newDataOperationError(key, "already set")
+		return newDataOperationError(ErrAlreadySet, key, "already set")
 	}
+	if d, decorated := a.decorations[key]; decorated {
+		// Consumers read the value once every decorator has wrapped it.
+		if d.available {
+			return newDataOperationError(ErrAlreadySet, key, "already set")
+		}
+		d.value, d.available = value, true
+		return nil
+	}
+	a.storeDataValue(key, value)
+	return nil
+}
+
+// storeDataValue stores a value in the assembly's data map and notifies waiters.
+// Returns true if any waiting module became ready.
+//
+// The caller must hold a.mu.
+func (a *assembly) storeDataValue(key DataKey, value any) bool {
 	a.data[key] = value
+	ready := false
+	for _, b := range a.waiters[key] {
+		if b.resolveDependency(key) {
+			a.schedule(b)
+			ready = true
+		}
+	}
+	delete(a.waiters, key)
+	return ready
+}
 
-	waiters := a.waiters[key]
-	if len(waiters) > 0 {
-		for _, b := range waiters {
-			if b.resolveDependency(key) {
-				a.ready.Push(b)
+// producersOf returns the modules that must be configured before the value of k is complete:
+// its producer and decorators, every contributor of a collection key, or the required module
+// of a module requirement.
+//
+// The caller must hold a.mu.
+func (a *assembly) producersOf(k DataKey) []*binder {
+	if _, ok := k.(collectionKey); ok {
+		return a.contributors[k]
+	}
+	if mk, ok := k.(moduleKey); ok {
+		if b, installed := a.bindings[mk.sig]; installed {
+			return []*binder{b}
+		}
+		return nil
+	}
+	var producers []*binder
+	if p, ok := a.producers[k]; ok {
+		producers = append(producers, p)
+	}
+	return append(producers, a.decorators[k]...)
+}
+
+// settle resolves the dependencies that can only be decided once no module is ready or being
+// configured. At that point no module can be installed until one is scheduled, so:
+//   - a collection key whose contributors have all been configured is complete, and is
+//     assembled for the modules waiting on it;
+//   - a module whose required modules have all been configured is notified (see [Requirer]);
+//   - the value of a decorated key is passed to its next decorator, or stored once every
+//     decorator has been configured (see [Decorator]);
+//   - a key without a producer whose value is held by a parent assembly is inherited by the
+//     modules waiting on it, or passed to its first decorator;
+//   - an optionally consumed key without a producer or a default value, or whose producer
+//     failed, will provably never be produced, and is resolved as absent for the modules
+//     waiting on it;
+//   - a key without a producer that has a default value (see [WithDefault]) is set to it, or
+//     its default value is passed to its first decorator;
+//   - once nothing else can progress, a collection key no module waits for is assembled.
+//
+// The resolutions are tried in this order, and settle returns as soon as one of them makes a
+// module ready: the module may install producers or contributors that change the outcome of
+// the others, which are only decided once it has been configured.
+//
+// Returns true if any module became ready.
+func (a *assembly) settle() bool {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.finalizeCollections(false) ||
+		a.resolveRequirements() ||
+		a.advanceDecorations() ||
+		a.resolveInherited() ||
+		a.resolveAbsent() ||
+		a.applyDefaults() ||
+		a.finalizeCollections(true)
+}
+
+// resolveInherited resolves the keys modules are waiting for that have no producer from the
+// parent assembly, including the undecorated value of decorated keys (see resolveUndecorated),
+// and returns as soon as a module becomes ready.
+//
+// Returns true if any module became ready.
+//
+// The caller must hold a.mu.
+func (a *assembly) resolveInherited() bool {
+	if a.resolveUndecorated(false) {
+		return true
+	}
+	for _, k := range sortedKeys(a.waiters) {
+		if len(a.producersOf(k)) > 0 || !a.parentHas(k) {
+			continue
+		}
+		a.inherited[k] = struct{}{}
+		progress := false
+		for _, b := range a.waiters[k] {
+			if b.resolveDependency(k) {
+				a.schedule(b)
+				progress = true
 			}
 		}
-		delete(a.waiters, key)
+		delete(a.waiters, k)
+		if progress {
+			return true
+		}
+	}
+	return false
+}
+
+// applyDefaults sets the keys modules are waiting for that have no producer, and no value
+// from the parent assembly, to their default value (see [WithDefault]), including the
+// undecorated value of decorated keys (see resolveUndecorated). It is only called once no
+// other resolution makes progress, since a module made ready by another may still install
+// their producer, and returns as soon as a module becomes ready.
+//
+// Returns true if any module became ready.
+//
+// The caller must hold a.mu.
+func (a *assembly) applyDefaults() bool {
+	if a.resolveUndecorated(true) {
+		return true
+	}
+	for _, k := range sortedKeys(a.waiters) {
+		if len(a.producersOf(k)) > 0 {
+			continue
+		}
+		dk, ok := k.(defaultedKey)
+		if !ok {
+			continue
+		}
+		if value, ok := dk.newDefault(); ok {
+			a.defaulted[k] = struct{}{}
+			a.log(context.Background(), slog.LevelDebug, "data defaulted", keyAttr(k))
+			if a.storeDataValue(k, value) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// resolveAbsent resolves the optionally consumed keys that have no live producer, and no value
+// from the parent assembly or a default, as absent for the modules waiting on them. It is
+// only called once no requirement, decoration or inherited value makes progress, since a
+// module made ready by those may still install their producer, and returns as soon as a
+// module becomes ready. The keys with a default are left to applyDefaults, which runs
+// afterwards so that a module made ready here can still install their producer.
+//
+// Returns true if any module became ready.
+//
+// The caller must hold a.mu.
+func (a *assembly) resolveAbsent() bool {
+	for _, k := range sortedKeys(a.waiters) {
+		producers := a.producersOf(k)
+		if len(producers) > 0 && !anyFailed(producers) && !a.undecoratable(k) {
+			continue
+		}
+		if len(producers) == 0 && hasDefault(k) {
+			continue
+		}
+		progress := false
+		var remaining []*binder
+		for _, b := range a.waiters[k] {
+			if _, optional := b.optional[k]; !optional {
+				remaining = append(remaining, b)
+				continue
+			}
+			if b.resolveDependency(k) {
+				a.schedule(b)
+				progress = true
+			}
+		}
+		if len(remaining) > 0 {
+			a.waiters[k] = remaining
+		} else {
+			delete(a.waiters, k)
+		}
+		if progress {
+			return true
+		}
+	}
+	return false
+}
+
+// anyFailed reports whether the configuration of any of the given modules failed.
+func anyFailed(binders []*binder) bool {
+	for _, b := range binders {
+		if b.failed.Load() {
+			return true
+		}
+	}
+	return false
+}
+
+// runModule configures the module bound to b under a context derived from ctx and bounded by
+// the module timeout, if any. The module is recorded in the configuration order used by Start
+// and Stop, its timing is recorded for the [BuildReport] and the [BuildObserver], if any, is
+// notified. A module whose configuration fails is marked as failed.
+//
+// If the context is done before Configure() returns, runModule returns immediately with a
+// [ConfigurationError] for the module. Configure() keeps running in the background, but
+// the binder rejects any further operation because its context is done.
+func (a *assembly) runModule(ctx context.Context, b *binder) error {
+	observer := a.options.observer
+	if observer != nil {
+		ctx = observer.ModuleStarted(ctx, b.moduleSignature.String())
+	}
+	a.mu.Lock()
+	a.order = append(a.order, b)
+	b.timing.started = time.Now()
+	a.mu.Unlock()
+	err := a.awaitModule(ctx, b)
+	if err != nil {
+		b.failed.Store(true)
+	}
+	a.mu.Lock()
+	b.timing.finished = time.Now()
+	b.timing.err = err
+	report := a.moduleReport(b)
+	a.mu.Unlock()
+	if observer != nil {
+		observer.ModuleFinished(ctx, report)
+	}
+	return err
+}
+
+// awaitModule runs configureModule for b and waits until it returns or ctx is done. If
+// Configure() exits its goroutine without returning, such as through [runtime.Goexit] called
+// by t.FailNow() in a test, awaitModule returns a [ConfigurationError] for the module.
+func (a *assembly) awaitModule(ctx context.Context, b *binder) error {
+	if a.options.moduleTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, a.options.moduleTimeout)
+		defer cancel()
+	}
+	done := make(chan error, 1)
+	go func() {
+		err := newNotReturnedError(b.moduleSignature.String())
+		defer func() { done <- err }()
+		err = b.configureModule(ctx)
+	}()
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		select {
+		case err := <-done:
+			// Configure() returned at the same time; prefer its result.
+			return err
+		default:
+		}
+		return &ConfigurationError{
+			ModuleID:  b.moduleSignature.String(),
+			Operation: "Configure",
+			Err:       fmt.Errorf("still configuring: %w", ctx.Err()),
+		}
+	}
+}
+
+// schedule adds a binder to the ready queue and wakes up Build if it is waiting for work.
+//
+// The caller must hold a.mu.
+func (a *assembly) schedule(b *binder) {
+	attrs := []slog.Attr{moduleAttr(b)}
+	if b.timing.unblockedBy != nil {
+		attrs = append(attrs, slog.String("unblocked_by", b.timing.unblockedBy.signature().String()))
+	}
+	a.log(context.Background(), slog.LevelDebug, "module scheduled", attrs...)
+	b.timing.ready = time.Now()
+	a.ready.Push(b)
+	select {
+	case a.wake <- struct{}{}:
+	default:
 	}
-	return nil
 }
 
 // NewAssembly creates a new Assembly instance with the specified modules.
@@ -213,20 +733,61 @@ func (a *assembly) putDataValue(key DataKey, value any) error {
 // Returns an error if the modules cannot be added to the assembly. On success, returns
 // an [Assembly] ready for the Build() process.
 func NewAssembly(modules ...Module) (Assembly, error) {
+	return NewAssemblyWithOptions(nil, modules...)
+}
+
+// NewAssemblyWithOptions creates a new Assembly instance with the specified options and modules.
+//
+// The options are applied in order before any module is installed; see [AssemblyOption].
+// Otherwise it behaves like [NewAssembly].
+//
+// Returns an error if an option is invalid or if the modules cannot be added to the assembly.
+func NewAssemblyWithOptions(opts []AssemblyOption, modules ...Module) (Assembly, error) {
+	options := defaultAssemblyOptions()
+	for _, opt := range opts {
+		if err := opt(&options); err != nil {
+			return nil, err
+		}
+	}
 	asm := &assembly{
-		mu:        sync.RWMutex{},
-		bindings:  make(map[moduleSignature]*binder),
-		registry:  newDataRegistry(),
-		data:      make(map[DataKey]any),
-		waiters:   make(map[DataKey][]*binder),
-		producers: make(map[DataKey]*binder),
-		ready:     make(binderQueue, 0),
+		mu:            sync.RWMutex{},
+		bindings:      make(map[moduleSignature]*binder),
+		registry:      newDataRegistry(),
+		data:          make(map[DataKey]any),
+		waiters:       make(map[DataKey][]*binder),
+		producers:     make(map[DataKey]*binder),
+		contributors:  make(map[DataKey][]*binder),
+		contributions: make(map[DataKey][]contribution),
+		inherited:     make(map[DataKey]struct{}),
+		defaulted:     make(map[DataKey]struct{}),
+		decorators:    make(map[DataKey][]*binder),
+		decorations:   make(map[DataKey]*decoration),
+		ready:         make(binderQueue, 0),
+		options:       options,
+		wake:          make(chan struct{}, 1),
+	}
+	if options.parent != nil {
+		// Keys of the parent must keep their signature in the child.
+		asm.registry = options.parent.registry.clone()
+	}
+	for _, k := range sortedKeys(options.seededData) {
+		if err := asm.registry.Validate(k); err != nil {
+			return nil, err
+		}
+		asm.data[k] = options.seededData[k]
 	}
+	var errs []error
 	for _, m := range modules {
 		if err := asm.install(m, nil); err != nil {
-			return nil, err
+			if !options.aggregateErrors {
+				return nil, err
+			}
+			errs = append(errs, err)
 		}
 	}
+	if len(errs) > 0 {
+		return nil, &AssemblyError{Errors: errs}
+	}
 	return asm, nil
 }
 
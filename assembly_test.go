@@ -1,8 +1,12 @@
 package modz
 
 import (
+	"context"
 	"fmt"
+	"runtime"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 )
@@ -472,3 +476,455 @@ func TestAssembly_ModuleIdentity_TypeAndName(t *testing.T) {
 	err = assembly.Build()
 	require.NoError(t, err)
 }
+
+func TestNewAssemblyWithOptions_InvalidParallelism(t *testing.T) {
+	asm, err := NewAssemblyWithOptions([]AssemblyOption{WithParallelism(0)}, &MockModule{NameValue: "m"})
+	require.Error(t, err)
+	require.Nil(t, asm)
+	require.Contains(t, err.Error(), "workers must be at least 1")
+}
+
+func TestAssembly_Build_Parallel(t *testing.T) {
+	// m1 and m2 are independent; each waits until both have started configuring,
+	// which can only succeed if they are configured concurrently.
+	var running atomic.Int32
+	bothRunning := make(chan struct{})
+	configure := func(b Binder) error {
+		if running.Add(1) == 2 {
+			close(bothRunning)
+		}
+		select {
+		case <-bothRunning:
+			return nil
+		case <-time.After(5 * time.Second):
+			return fmt.Errorf("timed out waiting for concurrent configuration")
+		}
+	}
+	m1 := &MockModule{NameValue: "m1", ConfigureFunc: configure}
+	m2 := &MockModule{NameValue: "m2", ConfigureFunc: configure}
+	asm, err := NewAssemblyWithOptions([]AssemblyOption{WithParallelism(2)}, m1, m2)
+	require.NoError(t, err)
+	require.NoError(t, asm.Build())
+}
+
+func TestAssembly_Build_ParallelSchedulesNewlyReadyModules(t *testing.T) {
+	// m1 produces FooKey and then waits for its consumer m2 to start, so m2 must be
+	// scheduled as soon as FooKey is Put rather than when m1 returns.
+	consumerStarted := make(chan struct{})
+	m1 := &MockModule{
+		NameValue:     "m1",
+		ProducesValue: Keys(FooKey),
+		ConfigureFunc: func(b Binder) error {
+			if err := FooKey.Put(b, 42); err != nil {
+				return err
+			}
+			select {
+			case <-consumerStarted:
+				return nil
+			case <-time.After(5 * time.Second):
+				return fmt.Errorf("timed out waiting for consumer")
+			}
+		},
+	}
+	m2 := &MockModule{
+		NameValue:     "m2",
+		ConsumesValue: Keys(FooKey),
+		ConfigureFunc: func(b Binder) error {
+			close(consumerStarted)
+			v, err := FooKey.Get(b)
+			if err != nil {
+				return err
+			}
+			require.Equal(t, 42, v)
+			return nil
+		},
+	}
+	asm, err := NewAssemblyWithOptions([]AssemblyOption{WithParallelism(2)}, m1, m2)
+	require.NoError(t, err)
+	require.NoError(t, asm.Build())
+}
+
+func TestAssembly_Build_ParallelStopsAfterError(t *testing.T) {
+	var configured atomic.Int32
+	failing := &MockModule{
+		NameValue:     "failing",
+		ProducesValue: Keys(FooKey),
+		ConfigureFunc: func(b Binder) error {
+			return fmt.Errorf("configure failed")
+		},
+	}
+	consumer := &MockModule{
+		NameValue:     "consumer",
+		ConsumesValue: Keys(FooKey),
+		ConfigureFunc: func(b Binder) error {
+			configured.Add(1)
+			return nil
+		},
+	}
+	asm, err := NewAssemblyWithOptions([]AssemblyOption{WithParallelism(4)}, failing, consumer)
+	require.NoError(t, err)
+
+	err = asm.Build()
+	var configErr *ConfigurationError
+	require.ErrorAs(t, err, &configErr)
+	require.Equal(t, "github.com/goosz/modz:failing", configErr.ModuleID)
+	require.Zero(t, configured.Load())
+}
+
+// exitingObserver is a BuildObserver exiting the goroutine configuring a module through
+// runtime.Goexit, as t.FailNow() does.
+type exitingObserver struct {
+	recordingObserver
+}
+
+func (o *exitingObserver) ModuleStarted(ctx context.Context, moduleID string) context.Context {
+	runtime.Goexit()
+	return ctx
+}
+
+func TestAssembly_Build_ModuleGoroutineExits(t *testing.T) {
+	for _, parallelism := range []int{1, 4} {
+		m := &MockModule{NameValue: "m", ProducesValue: Keys(FooKey)}
+		consumer := &MockModule{NameValue: "consumer", OptionalConsumesValue: Keys(FooKey)}
+		opts := []AssemblyOption{
+			WithParallelism(parallelism),
+			WithBuildObserver(&exitingObserver{}),
+			WithAggregateErrors(),
+		}
+		asm, err := NewAssemblyWithOptions(opts, m, consumer)
+		require.NoError(t, err)
+
+		err = asm.Build()
+		var asmErr *AssemblyError
+		require.ErrorAs(t, err, &asmErr)
+		require.Len(t, asmErr.Errors, 2)
+		require.EqualError(t, asmErr.Errors[0], "module 'github.com/goosz/modz:m' Configure: exited without returning")
+		require.True(t, asm.(*assembly).bindings[newModuleSignature(m)].failed.Load())
+	}
+}
+
+func TestNewAssemblyWithOptions_InvalidTimeouts(t *testing.T) {
+	_, err := NewAssemblyWithOptions([]AssemblyOption{WithBuildTimeout(0)})
+	require.ErrorContains(t, err, "WithBuildTimeout: timeout must be positive")
+	_, err = NewAssemblyWithOptions([]AssemblyOption{WithModuleTimeout(-time.Second)})
+	require.ErrorContains(t, err, "WithModuleTimeout: timeout must be positive")
+}
+
+func TestAssembly_BuildContext_Canceled(t *testing.T) {
+	called := false
+	m := &MockModule{
+		NameValue: "m",
+		ConfigureFunc: func(b Binder) error {
+			called = true
+			return nil
+		},
+	}
+	asm, err := NewAssembly(m)
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	err = asm.BuildContext(ctx)
+	require.ErrorIs(t, err, context.Canceled)
+	require.False(t, called, "no module should be configured with a canceled context")
+
+	_, err = asm.getData(FooKey)
+	require.Error(t, err)
+}
+
+func TestAssembly_BuildContext_ModuleObservesContext(t *testing.T) {
+	m := &MockModule{
+		NameValue: "m",
+		ConfigureFunc: func(b Binder) error {
+			<-b.Context().Done()
+			return b.Context().Err()
+		},
+	}
+	asm, err := NewAssemblyWithOptions([]AssemblyOption{WithModuleTimeout(10 * time.Millisecond)}, m)
+	require.NoError(t, err)
+
+	err = asm.BuildContext(context.Background())
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+	var configErr *ConfigurationError
+	require.ErrorAs(t, err, &configErr)
+	require.Equal(t, "github.com/goosz/modz:m", configErr.ModuleID)
+	require.Equal(t, "Configure", configErr.Operation)
+}
+
+func TestAssembly_BuildContext_HungModule(t *testing.T) {
+	// hung ignores its context entirely; Build must still return and name it.
+	release := make(chan struct{})
+	defer close(release)
+	producer := &MockModule{
+		NameValue:     "producer",
+		ProducesValue: Keys(FooKey),
+		ConfigureFunc: func(b Binder) error {
+			return FooKey.Put(b, 1)
+		},
+	}
+	hung := &MockModule{
+		NameValue:     "hung",
+		ConsumesValue: Keys(FooKey),
+		ConfigureFunc: func(b Binder) error {
+			<-release
+			return nil
+		},
+	}
+	asm, err := NewAssemblyWithOptions([]AssemblyOption{WithBuildTimeout(20 * time.Millisecond)}, producer, hung)
+	require.NoError(t, err)
+
+	err = asm.Build()
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+	var configErr *ConfigurationError
+	require.ErrorAs(t, err, &configErr)
+	require.Equal(t, "github.com/goosz/modz:hung", configErr.ModuleID)
+	require.Contains(t, err.Error(), "still configuring")
+}
+
+func TestAssembly_BuildContext_ConfigureExits(t *testing.T) {
+	// exiting calls runtime.Goexit, as t.FailNow() does; Build must still return and name it.
+	for _, opts := range [][]AssemblyOption{
+		nil,
+		{WithParallelism(4)},
+		{WithModuleTimeout(time.Minute)},
+	} {
+		exiting := &MockModule{
+			NameValue:     "exiting",
+			ProducesValue: Keys(FooKey),
+			ConfigureFunc: func(b Binder) error {
+				runtime.Goexit()
+				return nil
+			},
+		}
+		consumer := &MockModule{NameValue: "consumer", ConsumesValue: Keys(FooKey)}
+		asm, err := NewAssemblyWithOptions(opts, exiting, consumer)
+		require.NoError(t, err)
+
+		err = asm.Build()
+		require.EqualError(t, err, "module 'github.com/goosz/modz:exiting' Configure: exited without returning")
+		var configErr *ConfigurationError
+		require.ErrorAs(t, err, &configErr)
+		require.Equal(t, "github.com/goosz/modz:exiting", configErr.ModuleID)
+		require.False(t, asm.(*assembly).bindings[newModuleSignature(exiting)].inProgress.Load())
+	}
+}
+
+func TestAssembly_BuildContext_DeadlineVisibleToModules(t *testing.T) {
+	m := &MockModule{
+		NameValue: "m",
+		ConfigureFunc: func(b Binder) error {
+			_, ok := b.Context().Deadline()
+			require.True(t, ok, "the module context should carry the module deadline")
+			return nil
+		},
+	}
+	asm, err := NewAssemblyWithOptions([]AssemblyOption{WithModuleTimeout(time.Minute)}, m)
+	require.NoError(t, err)
+	require.NoError(t, asm.BuildContext(context.Background()))
+}
+
+func TestAssembly_Build_OptionalConsumes_Present(t *testing.T) {
+	producer := &MockModule{
+		NameValue:     "producer",
+		ProducesValue: Keys(FooKey),
+		ConfigureFunc: func(b Binder) error {
+			return FooKey.Put(b, 42)
+		},
+	}
+	var found bool
+	var value int
+	consumer := &MockModule{
+		NameValue:             "consumer",
+		OptionalConsumesValue: Keys(FooKey),
+		ConfigureFunc: func(b Binder) (err error) {
+			value, found, err = FooKey.Lookup(b)
+			return err
+		},
+	}
+	asm, err := NewAssembly(consumer, producer)
+	require.NoError(t, err)
+	require.NoError(t, asm.Build())
+	require.True(t, found)
+	require.Equal(t, 42, value)
+}
+
+func TestAssembly_Build_OptionalConsumes_Absent(t *testing.T) {
+	found := true
+	consumer := &MockModule{
+		NameValue:             "consumer",
+		ProducesValue:         Keys(BarKey),
+		OptionalConsumesValue: Keys(FooKey),
+		ConfigureFunc: func(b Binder) error {
+			var err error
+			_, found, err = FooKey.Lookup(b)
+			if err != nil {
+				return err
+			}
+			// Get reports the absence as an error without failing the module.
+			_, err = FooKey.Get(b)
+			require.ErrorIs(t, err, ErrNotProduced)
+			return BarKey.Put(b, 1)
+		},
+	}
+	downstream := &MockModule{
+		NameValue:     "downstream",
+		ConsumesValue: Keys(BarKey),
+	}
+	asm, err := NewAssembly(downstream, consumer)
+	require.NoError(t, err)
+	require.NoError(t, asm.Build())
+	require.False(t, found)
+
+	_, present, err := FooKey.Lookup(asm)
+	require.NoError(t, err)
+	require.False(t, present)
+}
+
+func TestAssembly_Build_OptionalConsumes_WaitsForInstalledProducer(t *testing.T) {
+	// The producer of FooKey is only installed during Build, so the consumer must not be
+	// configured before it.
+	producer := &MockModule{
+		NameValue:     "producer",
+		ProducesValue: Keys(FooKey),
+		ConfigureFunc: func(b Binder) error {
+			return FooKey.Put(b, 7)
+		},
+	}
+	installer := &MockModule{
+		NameValue: "installer",
+		ConfigureFunc: func(b Binder) error {
+			return b.Install(producer)
+		},
+	}
+	var found bool
+	consumer := &MockModule{
+		NameValue:             "consumer",
+		OptionalConsumesValue: Keys(FooKey),
+		ConfigureFunc: func(b Binder) (err error) {
+			_, found, err = FooKey.Lookup(b)
+			return err
+		},
+	}
+	asm, err := NewAssembly(consumer, installer)
+	require.NoError(t, err)
+	require.NoError(t, asm.Build())
+	require.True(t, found)
+}
+
+func TestAssembly_Build_OptionalConsumes_ProducerInstalledAfterSettling(t *testing.T) {
+	// The installer only becomes ready once settle resolves a key it consumes: ListKey, which
+	// has no contributor, or QuxKey, read from the parent assembly. FooKey must not be resolved
+	// as absent meanwhile.
+	parent, err := NewAssembly(&MockModule{
+		NameValue:     "qux",
+		ProducesValue: Keys(QuxKey),
+		ConfigureFunc: func(b Binder) error { return QuxKey.Put(b, 1) },
+	})
+	require.NoError(t, err)
+	require.NoError(t, parent.Build())
+
+	for _, consumes := range []DataKey{ListKey, QuxKey} {
+		t.Run(consumes.signature().name, func(t *testing.T) {
+			producer := &MockModule{
+				NameValue:     "producer",
+				ProducesValue: Keys(FooKey),
+				ConfigureFunc: func(b Binder) error {
+					return FooKey.Put(b, 7)
+				},
+			}
+			installer := &MockModule{
+				NameValue:     "installer",
+				ConsumesValue: Keys(consumes),
+				ConfigureFunc: func(b Binder) error {
+					return b.Install(producer)
+				},
+			}
+			var found bool
+			consumer := &MockModule{
+				NameValue:             "consumer",
+				OptionalConsumesValue: Keys(FooKey),
+				ConfigureFunc: func(b Binder) (err error) {
+					_, found, err = FooKey.Lookup(b)
+					return err
+				},
+			}
+			asm, err := NewAssemblyWithOptions([]AssemblyOption{WithParent(parent)}, consumer, installer)
+			require.NoError(t, err)
+			require.NoError(t, asm.Build())
+			require.True(t, found)
+		})
+	}
+}
+
+func TestAssembly_Build_OptionalConsumes_FailedProducer(t *testing.T) {
+	failing := &MockModule{
+		NameValue:     "failing",
+		ProducesValue: Keys(FooKey),
+		ConfigureFunc: func(b Binder) error {
+			return fmt.Errorf("configure failed")
+		},
+	}
+	configured := false
+	consumer := &MockModule{
+		NameValue:             "consumer",
+		OptionalConsumesValue: Keys(FooKey),
+		ConfigureFunc: func(b Binder) error {
+			configured = true
+			_, found, err := FooKey.Lookup(b)
+			require.False(t, found)
+			return err
+		},
+	}
+	asm, err := NewAssemblyWithOptions([]AssemblyOption{WithAggregateErrors()}, failing, consumer)
+	require.NoError(t, err)
+
+	err = asm.Build()
+	var asmErr *AssemblyError
+	require.ErrorAs(t, err, &asmErr)
+	require.Len(t, asmErr.Errors, 1)
+	require.Empty(t, asmErr.Skipped)
+	require.True(t, configured)
+}
+
+func TestAssembly_Build_OptionalConsumes_Cycle(t *testing.T) {
+	m1 := &MockModule{NameValue: "m1", ProducesValue: Keys(FooKey), OptionalConsumesValue: Keys(BarKey)}
+	m2 := &MockModule{NameValue: "m2", ProducesValue: Keys(BarKey), ConsumesValue: Keys(FooKey)}
+	asm, err := NewAssembly(m1, m2)
+	require.NoError(t, err)
+	require.ErrorIs(t, asm.Build(), ErrCycle)
+}
+
+func TestNewAssembly_OptionalConsumes_AlsoConsumed(t *testing.T) {
+	m := &MockModule{NameValue: "m", ConsumesValue: Keys(FooKey), OptionalConsumesValue: Keys(FooKey)}
+	_, err := NewAssembly(m)
+	require.ErrorIs(t, err, ErrDuplicateDeclaration)
+	require.EqualError(t, err, fmt.Sprintf("module 'github.com/goosz/modz:m' declares '%s' in both Consumes and OptionalConsumes", FooKey))
+	var modzErr *Error
+	require.ErrorAs(t, err, &modzErr)
+	require.Equal(t, FooKey, modzErr.Key)
+}
+
+func TestAssembly_Build_ConfigurePanics(t *testing.T) {
+	for _, parallelism := range []int{1, 4} {
+		panicking := &MockModule{
+			NameValue:     "panicking",
+			ProducesValue: Keys(FooKey),
+			ConfigureFunc: func(b Binder) error { panic("boom") },
+		}
+		consumer := &MockModule{NameValue: "consumer", ConsumesValue: Keys(FooKey)}
+		other := &MockModule{NameValue: "other"}
+		opts := []AssemblyOption{WithParallelism(parallelism), WithAggregateErrors()}
+		asm, err := NewAssemblyWithOptions(opts, panicking, consumer, other)
+		require.NoError(t, err)
+
+		err = asm.Build()
+		require.ErrorIs(t, err, ErrPanic)
+		var asmErr *AssemblyError
+		require.ErrorAs(t, err, &asmErr)
+		require.Len(t, asmErr.Errors, 1)
+		require.Equal(t, []string{"github.com/goosz/modz:consumer"}, asmErr.Skipped)
+
+		_, err = FooKey.Get(asm)
+		require.ErrorIs(t, err, ErrNotBuilt)
+	}
+}
@@ -1,8 +1,13 @@
 package modz
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"log/slog"
+	"runtime/debug"
 	"sync/atomic"
+	"time"
 
 	"github.com/goosz/commonz"
 )
@@ -41,6 +46,14 @@ type Binder interface {
 	// Returns an error if the module cannot be installed or if called outside of the
 	// module's configuration phase (strictly enforced).
 	Install(Module) error
+
+	// Context returns the context governing the module's configuration phase.
+	//
+	// The context is derived from the one passed to the [Assembly]'s BuildContext() and is
+	// bounded by the timeouts configured with [WithBuildTimeout] and [WithModuleTimeout].
+	// Modules that block during configuration should observe it and return its error once it
+	// is done. After the context is done, Install and the data access methods return an error.
+	Context() context.Context
 }
 
 // binder is the internal implementation used by [Assembly] to manage a module's lifecycle.
@@ -59,9 +72,12 @@ type binder struct {
 
 	assembly *assembly
 
-	// produces and consumes are populated in the discovery phase.
-	produces map[DataKey]struct{}
-	consumes map[DataKey]struct{}
+	// produces, consumes, optional, decorates and requires are populated in the discovery phase.
+	produces  map[DataKey]struct{}
+	consumes  map[DataKey]struct{}
+	optional  map[DataKey]struct{}
+	decorates map[DataKey]struct{}
+	requires  map[DataKey]struct{}
 
 	// waiting contains DataKeys waiting to be satisfied before this module's configuration can begin.
 	waiting map[DataKey]struct{}
@@ -69,10 +85,19 @@ type binder struct {
 	// produced tracks which DataKeys have been produced by this module during configuration.
 	produced map[DataKey]struct{}
 
+	// ctx is the context of the configuration phase, set by configureModule.
+	ctx context.Context
+
 	// inProgress is true while configureModule is running.
 	inProgress atomic.Bool
 	// configured is true after configureModule has run once.
 	configured atomic.Bool
+	// failed is true if the module's configuration failed or was abandoned.
+	failed atomic.Bool
+
+	// timing records the progress of the module through Build; it is protected by the
+	// assembly's mutex.
+	timing moduleTiming
 
 	// configurationError tracks the first error from binder operations (Install, getData, putData) during configuration
 	configurationError *ConfigurationError
@@ -84,11 +109,8 @@ var _ DataReader = (*binder)(nil)
 var _ DataWriter = (*binder)(nil)
 
 func (b *binder) Install(m Module) error {
-	if !b.inProgress.Load() {
-		return newPhaseError("Install")
-	}
-	if b.configurationError != nil {
-		return newFailFastError("Install", b.configurationError)
+	if err := b.checkOperation("Install"); err != nil {
+		return err
 	}
 	err := b.assembly.install(m, b)
 	if err != nil {
@@ -97,41 +119,82 @@ func (b *binder) Install(m Module) error {
 	return nil
 }
 
+func (b *binder) Context() context.Context {
+	return b.ctx
+}
+
 func (b *binder) getData(key DataKey) (any, error) {
-	if !b.inProgress.Load() {
-		return nil, newPhaseError("getData")
+	if err := b.checkOperation("getData"); err != nil {
+		return nil, err
 	}
-	if b.configurationError != nil {
-		return nil, newFailFastError("getData", b.configurationError)
-	}
-	if _, ok := b.consumes[key]; !ok {
+	_, consumed := b.consumes[key]
+	_, optional := b.optional[key]
+	_, decorated := b.decorates[key]
+	if !consumed && !optional && !decorated {
 		return nil, b.trackConfigurationError("getData", newUndeclaredKeyError(b.moduleSignature.String(), key, "Consumes"))
 	}
-	val, err := b.assembly.getDataValue(key)
+	var val any
+	var err error
+	if decorated {
+		val, err = b.assembly.decoratedValue(b, key)
+	} else {
+		val, err = b.assembly.getDataValue(key)
+	}
 	if err != nil {
+		if optional && errors.Is(err, ErrNotProduced) {
+			// An absent optional key is not a configuration error; see Data.Lookup.
+			return nil, err
+		}
 		return nil, b.trackConfigurationError("getData", err)
 	}
+	b.assembly.log(b.ctx, slog.LevelDebug, "data get", moduleAttr(b), keyAttr(key))
 	return val, nil
 }
 
 func (b *binder) putData(key DataKey, value any) error {
-	if !b.inProgress.Load() {
-		return newPhaseError("putData")
-	}
-	if b.configurationError != nil {
-		return newFailFastError("putData", b.configurationError)
+	if err := b.checkOperation("putData"); err != nil {
+		return err
 	}
-	if _, ok := b.produces[key]; !ok {
+	_, produced := b.produces[key]
+	_, decorated := b.decorates[key]
+	if !produced && !decorated {
 		return b.trackConfigurationError("putData", newUndeclaredKeyError(b.moduleSignature.String(), key, "Produces"))
 	}
-	err := b.assembly.putDataValue(key, value)
+	if lv, ok := value.(*lazyValue); ok {
+		lv.moduleID = b.moduleSignature.String()
+	}
+	var err error
+	if ck, ok := key.(collectionKey); ok {
+		err = b.assembly.contributeDataValue(b, ck, value)
+	} else if decorated {
+		err = b.assembly.decorateDataValue(b, key, value)
+	} else {
+		err = b.assembly.putDataValue(key, value)
+	}
 	if err != nil {
 		return b.trackConfigurationError("putData", err)
 	}
+	b.assembly.log(b.ctx, slog.LevelDebug, "data put", moduleAttr(b), keyAttr(key))
 	b.produced[key] = struct{}{}
 	return nil
 }
 
+// checkOperation verifies that a binder operation may run: it must be called during the
+// configuration phase, no earlier operation may have failed, and the configuration context
+// must not be done.
+func (b *binder) checkOperation(operation string) error {
+	if !b.inProgress.Load() {
+		return newPhaseError(operation)
+	}
+	if b.configurationError != nil {
+		return newFailFastError(operation, b.configurationError)
+	}
+	if err := b.ctx.Err(); err != nil {
+		return b.trackConfigurationError(operation, err)
+	}
+	return nil
+}
+
 // trackConfigurationError creates a ConfigurationError for binder operations, tracks it, and returns it.
 // Only the first binder operation error is tracked; subsequent errors are ignored.
 func (b *binder) trackConfigurationError(operation string, err error) *ConfigurationError {
@@ -145,22 +208,76 @@ func (b *binder) trackConfigurationError(operation string, err error) *Configura
 	return b.configurationError
 }
 
-// discoverModule performs the module discovery phase, populating produces and consumes.
+// discoverModule performs the module discovery phase, populating produces, consumes, optional,
+// decorates and requires.
 func (b *binder) discoverModule() error {
 	produces, err := commonz.SliceToSet(b.module.Produces(), true)
 	if err != nil {
-		return fmt.Errorf("failed to convert produces to set: %w", err)
+		return newDiscoveryError(b.moduleSignature.String(), "Produces", err)
 	}
 	consumes, err := commonz.SliceToSet(b.module.Consumes(), true)
 	if err != nil {
-		return fmt.Errorf("failed to convert consumes to set: %w", err)
+		return newDiscoveryError(b.moduleSignature.String(), "Consumes", err)
+	}
+	optional := make(map[DataKey]struct{})
+	if oc, ok := b.module.(OptionalConsumer); ok {
+		optional, err = commonz.SliceToSet(oc.OptionalConsumes(), true)
+		if err != nil {
+			return newDiscoveryError(b.moduleSignature.String(), "OptionalConsumes", err)
+		}
+		for _, k := range sortedKeys(optional) {
+			if _, ok := consumes[k]; ok {
+				return newOverlappingDeclarationError(b.moduleSignature.String(), k, "Consumes", "OptionalConsumes")
+			}
+		}
+	}
+	decorates := make(map[DataKey]struct{})
+	if d, ok := b.module.(Decorator); ok {
+		decorates, err = commonz.SliceToSet(d.Decorates(), true)
+		if err != nil {
+			return newDiscoveryError(b.moduleSignature.String(), "Decorates", err)
+		}
+		for _, k := range sortedKeys(decorates) {
+			declaration := ""
+			if _, ok := produces[k]; ok {
+				declaration = "Produces"
+			} else if _, ok := consumes[k]; ok {
+				declaration = "Consumes"
+			} else if _, ok := optional[k]; ok {
+				declaration = "OptionalConsumes"
+			}
+			if declaration != "" {
+				return newOverlappingDeclarationError(b.moduleSignature.String(), k, declaration, "Decorates")
+			}
+		}
+	}
+	requires := make(map[DataKey]struct{})
+	if r, ok := b.module.(Requirer); ok {
+		for _, m := range r.Requires() {
+			if m == nil {
+				return newInstallError(ErrInvalidArgument, b.moduleSignature.String(), "cannot require nil module")
+			}
+			requires[moduleKey{sig: newModuleSignature(m)}] = struct{}{}
+		}
 	}
 	b.produces = produces
 	b.consumes = consumes
-	// initialize waiting as a copy of consumes
+	b.optional = optional
+	b.decorates = decorates
+	b.requires = requires
+	// initialize waiting as a copy of consumes, optional, decorates and requires
 	for k := range consumes {
 		b.waiting[k] = struct{}{}
 	}
+	for k := range optional {
+		b.waiting[k] = struct{}{}
+	}
+	for k := range decorates {
+		b.waiting[k] = struct{}{}
+	}
+	for k := range requires {
+		b.waiting[k] = struct{}{}
+	}
 	return nil
 }
 
@@ -172,22 +289,40 @@ func (b *binder) isReady() bool {
 // resolveDependency marks the given DataKey as satisfied and returns true if all dependencies are now satisfied.
 func (b *binder) resolveDependency(k DataKey) bool {
 	delete(b.waiting, k)
-	return len(b.waiting) == 0
+	if len(b.waiting) > 0 {
+		return false
+	}
+	b.timing.unblockedBy = k
+	return true
 }
 
 // configureModule calls the module's Configure method with this binder and checks all declared produces keys were produced.
+// The context is made available to the module through Context(). A panic in Configure is
+// recovered and reported as a [PanicError] wrapped in a ConfigurationError.
 // It can only be called once; subsequent calls return an error.
-func (b *binder) configureModule() error {
+func (b *binder) configureModule(ctx context.Context) error {
 	if !b.configured.CompareAndSwap(false, true) {
 		return fmt.Errorf("configureModule: can only be called once")
 	}
-	b.inProgress.Store(true)
-	err := b.module.Configure(b)
-	b.inProgress.Store(false)
+	b.ctx = ctx
+	if err := ctx.Err(); err != nil {
+		return b.trackConfigurationError("Configure", err)
+	}
+	panicErr, err := b.callConfigure()
+
+	if panicErr != nil {
+		// A panic takes precedence over any error tracked before it.
+		b.configurationError = &ConfigurationError{
+			ModuleID:  b.moduleSignature.String(),
+			Operation: "Configure",
+			Err:       panicErr,
+		}
+		return b.configurationError
+	}
 
 	// Validate error handling: if the module returned nil but we tracked binder operation errors, that's suspicious
 	if err == nil && b.configurationError != nil {
-		swallowedError := fmt.Errorf("module returned nil error but encountered binder operation errors during configuration: %v", b.configurationError)
+		swallowedError := fmt.Errorf("module returned nil error but encountered binder operation errors during configuration: %w", b.configurationError)
 		return b.trackConfigurationError("Configure", swallowedError)
 	}
 
@@ -196,19 +331,36 @@ func (b *binder) configureModule() error {
 	}
 
 	// Check that all declared produces keys were actually produced
-	var missing []DataKey
-	for k := range b.produces {
+	var missing []error
+	for _, k := range sortedKeys(b.produces) {
+		if _, ok := k.(collectionKey); ok {
+			// Contributing to a collection is optional.
+			continue
+		}
 		if _, ok := b.produced[k]; !ok {
-			missing = append(missing, k)
+			missing = append(missing, newNotProducedError(b.moduleSignature.String(), k))
 		}
 	}
 	if len(missing) > 0 {
-		missingKeysError := fmt.Errorf("module did not produce all declared keys: %v", missing)
+		missingKeysError := fmt.Errorf("module did not produce all declared keys: %w", errors.Join(missing...))
 		return b.trackConfigurationError("Configure", missingKeysError)
 	}
 	return nil
 }
 
+// callConfigure calls the module's Configure method, recovering from a panic into a PanicError.
+// The binder is in progress until Configure returns, panics or exits through [runtime.Goexit].
+func (b *binder) callConfigure() (panicErr *PanicError, err error) {
+	b.inProgress.Store(true)
+	defer func() {
+		b.inProgress.Store(false)
+		if r := recover(); r != nil {
+			panicErr = &PanicError{Value: r, Stack: debug.Stack()}
+		}
+	}()
+	return nil, b.module.Configure(b)
+}
+
 // GetConfigurationError returns the first binder operation error encountered during configuration, if any.
 // This method is intended for introspection and debugging purposes.
 func (b *binder) GetConfigurationError() *ConfigurationError {
@@ -223,8 +375,13 @@ func newBinder(a *assembly, m Module, parent *binder, sig moduleSignature) *bind
 		module:          m,
 		parent:          parent,
 		assembly:        a,
+		ctx:             context.Background(),
+		timing:          moduleTiming{installed: time.Now()},
 		produces:        make(map[DataKey]struct{}),
 		consumes:        make(map[DataKey]struct{}),
+		optional:        make(map[DataKey]struct{}),
+		decorates:       make(map[DataKey]struct{}),
+		requires:        make(map[DataKey]struct{}),
 		waiting:         make(map[DataKey]struct{}),
 		produced:        make(map[DataKey]struct{}),
 		// configurationError starts as nil
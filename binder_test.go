@@ -1,6 +1,7 @@
 package modz
 
 import (
+	"context"
 	"errors"
 	"testing"
 
@@ -27,7 +28,7 @@ func TestBinder_Install(t *testing.T) {
 	b, asm := newBinderTestFixture(mod)
 
 	// this will call Install() via mod.ConfigureFunc above.
-	err := b.configureModule()
+	err := b.configureModule(context.Background())
 	require.NoError(t, err)
 
 	// check that the second module was added to the assembly.
@@ -67,7 +68,7 @@ func TestBinder_getData(t *testing.T) {
 	require.NoError(t, err)
 
 	// this will call getData() via mod.ConfigureFunc above.
-	err = b.configureModule()
+	err = b.configureModule(context.Background())
 	require.NoError(t, err)
 }
 
@@ -107,7 +108,7 @@ func TestBinder_getData_undeclaredKey(t *testing.T) {
 	require.NoError(t, err)
 
 	// this will call getData() via mod.ConfigureFunc above.
-	err = b.configureModule()
+	err = b.configureModule(context.Background())
 	require.Error(t, err)
 }
 
@@ -126,7 +127,7 @@ func TestBinder_getData_assemblyError(t *testing.T) {
 	err := b.discoverModule()
 	require.NoError(t, err)
 
-	err = b.configureModule()
+	err = b.configureModule(context.Background())
 	require.Error(t, err)
 
 	// Verify it's a ConfigurationError with proper context
@@ -154,7 +155,7 @@ func TestBinder_putData(t *testing.T) {
 	require.NoError(t, err)
 
 	// this will call putData() via mod.ConfigureFunc above.
-	err = b.configureModule()
+	err = b.configureModule(context.Background())
 	require.NoError(t, err)
 
 	// check that the value was added to the assembly.
@@ -193,7 +194,7 @@ func TestBinder_putData_undeclaredKey(t *testing.T) {
 	require.NoError(t, err)
 
 	// this will call putData() via mod.ConfigureFunc above.
-	err = b.configureModule()
+	err = b.configureModule(context.Background())
 	require.Error(t, err)
 
 	// check that the value was not added to the assembly.
@@ -221,7 +222,7 @@ func TestBinder_putData_assemblyError(t *testing.T) {
 	err := b.discoverModule()
 	require.NoError(t, err)
 
-	err = b.configureModule()
+	err = b.configureModule(context.Background())
 	require.Error(t, err)
 
 	// Verify it's a ConfigurationError with proper context
@@ -312,7 +313,7 @@ func TestBinder_configureModule(t *testing.T) {
 	require.NoError(t, err)
 
 	// this will call mod.ConfigureFunc above.
-	err = b.configureModule()
+	err = b.configureModule(context.Background())
 	require.NoError(t, err)
 	require.True(t, called)
 
@@ -333,7 +334,7 @@ func TestBinder_configureModule_error(t *testing.T) {
 	require.NoError(t, err)
 
 	// this will call mod.ConfigureFunc above.
-	err = b.configureModule()
+	err = b.configureModule(context.Background())
 	require.Error(t, err)
 
 	// Verify it's a ConfigurationError with proper context
@@ -349,6 +350,54 @@ func TestBinder_configureModule_error(t *testing.T) {
 	require.Contains(t, trackedError.Error(), "error")
 }
 
+func TestBinder_configureModule_panic(t *testing.T) {
+	mod := &MockModule{
+		NameValue:     "panic",
+		ProducesValue: Keys(ProducedKey),
+		ConfigureFunc: func(binder Binder) error {
+			// An earlier binder error must not hide the panic.
+			_ = ConsumedKey.Put(binder, 1)
+			panic("boom")
+		},
+	}
+	b, _ := newBinderTestFixture(mod)
+	err := b.discoverModule()
+	require.NoError(t, err)
+
+	err = b.configureModule(context.Background())
+	require.ErrorIs(t, err, ErrPanic)
+	require.False(t, b.inProgress.Load())
+
+	var configErr *ConfigurationError
+	require.ErrorAs(t, err, &configErr)
+	require.Equal(t, "github.com/goosz/modz:panic", configErr.ModuleID)
+	require.Equal(t, "Configure", configErr.Operation)
+
+	var panicErr *PanicError
+	require.ErrorAs(t, err, &panicErr)
+	require.Equal(t, "boom", panicErr.Value)
+	require.Contains(t, string(panicErr.Stack), "TestBinder_configureModule_panic")
+	require.Contains(t, err.Error(), "panic: boom")
+	require.Same(t, configErr, b.GetConfigurationError())
+
+	// The binder is no longer usable.
+	require.ErrorIs(t, ProducedKey.Put(b, "value"), ErrPhase)
+}
+
+func TestBinder_configureModule_panicWithError(t *testing.T) {
+	cause := errors.New("cause")
+	mod := &MockModule{
+		NameValue:     "panic",
+		ConfigureFunc: func(binder Binder) error { panic(cause) },
+	}
+	b, _ := newBinderTestFixture(mod)
+	require.NoError(t, b.discoverModule())
+
+	err := b.configureModule(context.Background())
+	require.ErrorIs(t, err, ErrPanic)
+	require.ErrorIs(t, err, cause)
+}
+
 func TestBinder_configureModule_declaredButNotProduced(t *testing.T) {
 	mod := &MockModule{
 		NameValue:     "mod",
@@ -362,7 +411,7 @@ func TestBinder_configureModule_declaredButNotProduced(t *testing.T) {
 	require.NoError(t, err)
 
 	// this will call mod.ConfigureFunc above.
-	err = b.configureModule()
+	err = b.configureModule(context.Background())
 	require.Error(t, err)
 
 	// Verify it's a ConfigurationError with proper context
@@ -389,10 +438,10 @@ func TestBinder_configureModule_twice(t *testing.T) {
 	err := b.discoverModule()
 	require.NoError(t, err)
 
-	err = b.configureModule()
+	err = b.configureModule(context.Background())
 	require.NoError(t, err)
 
-	err = b.configureModule()
+	err = b.configureModule(context.Background())
 	require.Error(t, err, "second call to configureModule should return an error")
 }
 
@@ -479,7 +528,7 @@ func TestBinder_failFastBehavior(t *testing.T) {
 	err := b.discoverModule()
 	require.NoError(t, err)
 
-	err = b.configureModule()
+	err = b.configureModule(context.Background())
 	require.Error(t, err)
 
 	// Verify it's a ConfigurationError with the first error
@@ -494,3 +543,42 @@ func TestBinder_failFastBehavior(t *testing.T) {
 	require.NotNil(t, trackedError)
 	require.Contains(t, trackedError.Error(), "data key 'Data[string](github.com/goosz/modz:produced#4)': already set")
 }
+
+func TestBinder_Context(t *testing.T) {
+	type ctxKey struct{}
+	ctx := context.WithValue(context.Background(), ctxKey{}, "value")
+	mod := &MockModule{
+		NameValue: "mod",
+		ConfigureFunc: func(b Binder) error {
+			require.Equal(t, "value", b.Context().Value(ctxKey{}))
+			return nil
+		},
+	}
+	b, _ := newBinderTestFixture(mod)
+	require.NotNil(t, b.Context(), "Context should never be nil")
+	require.NoError(t, b.discoverModule())
+	require.NoError(t, b.configureModule(ctx))
+}
+
+func TestBinder_operationsAfterContextDone(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	mod := &MockModule{
+		NameValue:     "mod",
+		ProducesValue: Keys(ProducedKey),
+		ConfigureFunc: func(b Binder) error {
+			cancel()
+			return ProducedKey.Put(b, "late")
+		},
+	}
+	b, asm := newBinderTestFixture(mod)
+	require.NoError(t, b.discoverModule())
+
+	err := b.configureModule(ctx)
+	require.ErrorIs(t, err, context.Canceled)
+	var configErr *ConfigurationError
+	require.ErrorAs(t, err, &configErr)
+	require.Equal(t, "putData", configErr.Operation)
+
+	_, found := asm.data[ProducedKey]
+	require.False(t, found)
+}
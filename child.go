@@ -0,0 +1,100 @@
+package modz
+
+import (
+	"fmt"
+)
+
+// WithParent makes the [Assembly] a child of a built parent Assembly.
+//
+// The modules of a child assembly can consume the [Data] of its parent, and of the parent's
+// own ancestors, without any module producing it again: a consumed key that no module of the
+// child produces is read from the parent once the child can make no other progress. Modules
+// of the child may also produce keys the parent already holds; their values shadow the
+// parent's within the child. Contributions to a [SetData] or [MapData] key extend the
+// parent's: the child's collection holds the parent's contributions followed by its own, and
+// the child's [MapData] entries replace the parent's entries with the same key.
+//
+// A [Singleton] module already installed in an ancestor is not installed again in the child;
+// its values are read from the ancestor. Other modules may be installed in both.
+//
+// The child is built, started and discarded independently. It never modifies its parent,
+// so many children can be created from the same parent, even concurrently. Once a child
+// module has read a key from the parent, no module producing that key can be installed in
+// the child anymore.
+//
+// Returns an error from [NewAssemblyWithOptions] if parent is nil or has not been built
+// successfully.
+func WithParent(parent Assembly) AssemblyOption {
+	return func(o *assemblyOptions) error {
+		p, ok := parent.(*assembly)
+		if !ok || p == nil {
+			return fmt.Errorf("WithParent: parent must not be nil")
+		}
+		if !p.buildCompleted.Load() {
+			return fmt.Errorf("WithParent: parent must be built successfully")
+		}
+		o.parent = p
+		return nil
+	}
+}
+
+// parentHas reports whether an ancestor of the assembly holds a value for k, or has installed
+// the module required by a module requirement.
+func (a *assembly) parentHas(k DataKey) bool {
+	if mk, ok := k.(moduleKey); ok {
+		// A module required from a parent assembly was configured when the parent was built.
+		return a.parentHasModule(mk.sig)
+	}
+	for p := a.options.parent; p != nil; p = p.options.parent {
+		p.mu.RLock()
+		_, ok := p.data[k]
+		p.mu.RUnlock()
+		if ok {
+			return true
+		}
+	}
+	return false
+}
+
+// parentValue returns the value held for k by the nearest ancestor of the assembly holding
+// one, as stored: a value provided lazily is not computed.
+func (a *assembly) parentValue(k DataKey) (any, bool) {
+	for p := a.options.parent; p != nil; p = p.options.parent {
+		p.mu.RLock()
+		value, ok := p.data[k]
+		p.mu.RUnlock()
+		if ok {
+			return value, true
+		}
+	}
+	return nil, false
+}
+
+// parentHasModule reports whether a module with the given signature is installed in an
+// ancestor of the assembly.
+func (a *assembly) parentHasModule(sig moduleSignature) bool {
+	for p := a.options.parent; p != nil; p = p.options.parent {
+		p.mu.RLock()
+		_, ok := p.bindings[sig]
+		p.mu.RUnlock()
+		if ok {
+			return true
+		}
+	}
+	return false
+}
+
+// inheritedContributions returns the ordered contributions to the collection key k held by
+// the nearest ancestor that assembled it, if any.
+func (a *assembly) inheritedContributions(k DataKey) []contribution {
+	for p := a.options.parent; p != nil; p = p.options.parent {
+		p.mu.RLock()
+		_, ok := p.data[k]
+		contributions := p.contributions[k]
+		p.mu.RUnlock()
+		if ok {
+			return contributions
+		}
+	}
+	return nil
+}
@@ -0,0 +1,197 @@
+package modz
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// newBuiltParent returns a built assembly producing FooKey, ListKey and IndexKey.
+func newBuiltParent(t *testing.T) Assembly {
+	t.Helper()
+	m := &MockModule{
+		NameValue:     "parent",
+		ProducesValue: Keys(FooKey, ListKey, IndexKey),
+		ConfigureFunc: func(b Binder) error {
+			if err := FooKey.Put(b, 1); err != nil {
+				return err
+			}
+			if err := ListKey.Add(b, "parent"); err != nil {
+				return err
+			}
+			if err := IndexKey.Put(b, "shared", 1); err != nil {
+				return err
+			}
+			return IndexKey.Put(b, "parent", 1)
+		},
+	}
+	parent, err := NewAssembly(m)
+	require.NoError(t, err)
+	require.NoError(t, parent.Build())
+	return parent
+}
+
+func TestWithParent_InheritsData(t *testing.T) {
+	parent := newBuiltParent(t)
+	var got int
+	consumer := &MockModule{
+		NameValue:     "consumer",
+		ProducesValue: Keys(BarKey),
+		ConsumesValue: Keys(FooKey),
+		ConfigureFunc: func(b Binder) error {
+			var err error
+			got, err = FooKey.Get(b)
+			if err != nil {
+				return err
+			}
+			return BarKey.Put(b, got+1)
+		},
+	}
+	child, err := NewAssemblyWithOptions([]AssemblyOption{WithParent(parent)}, consumer)
+	require.NoError(t, err)
+	require.NoError(t, child.Build())
+	require.Equal(t, 1, got)
+
+	foo, err := FooKey.Get(child)
+	require.NoError(t, err)
+	require.Equal(t, 1, foo)
+	bar, err := BarKey.Get(child)
+	require.NoError(t, err)
+	require.Equal(t, 2, bar)
+
+	// The parent is unchanged.
+	_, err = BarKey.Get(parent)
+	require.ErrorIs(t, err, ErrNotProduced)
+}
+
+func TestWithParent_ShadowsData(t *testing.T) {
+	parent := newBuiltParent(t)
+	var got int
+	// The consumer is installed before the producer that shadows the parent's value.
+	consumer := &MockModule{
+		NameValue:     "consumer",
+		ConsumesValue: Keys(FooKey),
+		ConfigureFunc: func(b Binder) error {
+			var err error
+			got, err = FooKey.Get(b)
+			return err
+		},
+	}
+	producer := &MockModule{
+		NameValue:     "producer",
+		ProducesValue: Keys(FooKey),
+		ConfigureFunc: func(b Binder) error { return FooKey.Put(b, 100) },
+	}
+	child, err := NewAssemblyWithOptions([]AssemblyOption{WithParent(parent)}, consumer, producer)
+	require.NoError(t, err)
+	require.NoError(t, child.Build())
+	require.Equal(t, 100, got)
+
+	foo, err := FooKey.Get(parent)
+	require.NoError(t, err)
+	require.Equal(t, 1, foo)
+}
+
+func TestWithParent_ExtendsCollections(t *testing.T) {
+	parent := newBuiltParent(t)
+	contributor := &MockModule{
+		NameValue:     "contributor",
+		ProducesValue: Keys(ListKey, IndexKey),
+		ConfigureFunc: func(b Binder) error {
+			if err := ListKey.Add(b, "child"); err != nil {
+				return err
+			}
+			return IndexKey.Put(b, "shared", 2)
+		},
+	}
+	consumer := &MockModule{NameValue: "consumer", ConsumesValue: Keys(ListKey, IndexKey)}
+	child, err := NewAssemblyWithOptions([]AssemblyOption{WithParent(parent)}, contributor, consumer)
+	require.NoError(t, err)
+	require.NoError(t, child.Build())
+
+	list, err := ListKey.Get(child)
+	require.NoError(t, err)
+	require.Equal(t, []string{"parent", "child"}, list)
+	index, err := IndexKey.Get(child)
+	require.NoError(t, err)
+	require.Equal(t, map[string]int{"shared": 2, "parent": 1}, index)
+
+	list, err = ListKey.Get(parent)
+	require.NoError(t, err)
+	require.Equal(t, []string{"parent"}, list)
+
+	// A grandchild without contributors inherits the child's collection.
+	grandchild, err := NewAssemblyWithOptions([]AssemblyOption{WithParent(child)},
+		&MockModule{NameValue: "consumer", ConsumesValue: Keys(ListKey)})
+	require.NoError(t, err)
+	require.NoError(t, grandchild.Build())
+	list, err = ListKey.Get(grandchild)
+	require.NoError(t, err)
+	require.Equal(t, []string{"parent", "child"}, list)
+}
+
+func TestWithParent_ProducerAfterInheritance(t *testing.T) {
+	parent := newBuiltParent(t)
+	producer := &MockModule{
+		NameValue:     "producer",
+		ProducesValue: Keys(FooKey),
+		ConfigureFunc: func(b Binder) error { return FooKey.Put(b, 100) },
+	}
+	consumer := &MockModule{
+		NameValue:     "consumer",
+		ConsumesValue: Keys(FooKey),
+		ConfigureFunc: func(b Binder) error { return b.Install(producer) },
+	}
+	child, err := NewAssemblyWithOptions([]AssemblyOption{WithParent(parent)}, consumer)
+	require.NoError(t, err)
+
+	err = child.Build()
+	require.ErrorIs(t, err, ErrAlreadySet)
+	require.Contains(t, err.Error(), "already read from the parent assembly")
+}
+
+func TestWithParent_SharesSingletons(t *testing.T) {
+	configured := 0
+	singleton := &MockSingletonModule{
+		NameValue:     "singleton",
+		ProducesValue: Keys(QuxKey),
+		ConfigureFunc: func(b Binder) error {
+			configured++
+			return QuxKey.Put(b, 9)
+		},
+	}
+	parent, err := NewAssembly(singleton)
+	require.NoError(t, err)
+	require.NoError(t, parent.Build())
+
+	child, err := NewAssemblyWithOptions([]AssemblyOption{WithParent(parent)},
+		singleton, &MockModule{NameValue: "consumer", ConsumesValue: Keys(QuxKey)})
+	require.NoError(t, err)
+	require.NoError(t, child.Build())
+	require.Equal(t, 1, configured)
+
+	qux, err := QuxKey.Get(child)
+	require.NoError(t, err)
+	require.Equal(t, 9, qux)
+}
+
+func TestWithParent_MissingProducer(t *testing.T) {
+	parent := newBuiltParent(t)
+	child, err := NewAssemblyWithOptions([]AssemblyOption{WithParent(parent)},
+		&MockModule{NameValue: "consumer", ConsumesValue: Keys(BazKey)})
+	require.NoError(t, err)
+
+	var missingErr *MissingProducerError
+	require.ErrorAs(t, child.Build(), &missingErr)
+	require.Equal(t, BazKey, missingErr.Key)
+}
+
+func TestWithParent_InvalidParent(t *testing.T) {
+	_, err := NewAssemblyWithOptions([]AssemblyOption{WithParent(nil)})
+	require.ErrorContains(t, err, "must not be nil")
+
+	unbuilt, err := NewAssembly()
+	require.NoError(t, err)
+	_, err = NewAssemblyWithOptions([]AssemblyOption{WithParent(unbuilt)})
+	require.ErrorContains(t, err, "must be built")
+}
@@ -0,0 +1,96 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"go/token"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"text/template"
+
+	"github.com/goosz/modz"
+)
+
+// harness is the program generated to print the dependency graph of a package's modules.
+var harness = template.Must(template.New("harness").Parse(`// Code generated by modz. DO NOT EDIT.
+
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/goosz/modz"
+	target {{printf "%q" .Package}}
+)
+
+func main() {
+	asm, err := modz.NewAssembly(target.{{.Func}}()...)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	if err := asm.Graph().WriteJSON(os.Stdout); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+`))
+
+// loadGraph returns the dependency graph of the modules returned by the function funcName
+// of the package pkg, by running a generated harness program with go run. The package
+// pattern pkg must match a single package.
+func loadGraph(pkg, funcName string) (*modz.Graph, error) {
+	if !token.IsIdentifier(funcName) || !token.IsExported(funcName) {
+		return nil, fmt.Errorf("invalid function name %q: must be an exported identifier", funcName)
+	}
+	out, err := goCommand("list", "-f", "{{.ImportPath}}", pkg)
+	if err != nil {
+		return nil, err
+	}
+	importPaths := strings.Fields(out)
+	if len(importPaths) != 1 {
+		return nil, fmt.Errorf("package pattern %q matches %d packages, must match exactly one", pkg, len(importPaths))
+	}
+
+	dir, err := os.MkdirTemp("", "modz-harness-")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(dir)
+	file := filepath.Join(dir, "main.go")
+	var src bytes.Buffer
+	err = harness.Execute(&src, struct{ Package, Func string }{importPaths[0], funcName})
+	if err != nil {
+		return nil, err
+	}
+	if err := os.WriteFile(file, src.Bytes(), 0o600); err != nil {
+		return nil, err
+	}
+
+	out, err = goCommand("run", file)
+	if err != nil {
+		return nil, err
+	}
+	var g modz.Graph
+	if err := json.Unmarshal([]byte(out), &g); err != nil {
+		return nil, fmt.Errorf("decoding graph: %w", err)
+	}
+	return &g, nil
+}
+
+// goCommand runs the go command with the given arguments in the current directory and
+// returns its standard output.
+func goCommand(args ...string) (string, error) {
+	cmd := exec.Command("go", args...)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("go %s: %w\n%s", args[0], err, strings.TrimSpace(stderr.String()))
+	}
+	return stdout.String(), nil
+}
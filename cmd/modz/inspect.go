@@ -0,0 +1,218 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+
+	"github.com/goosz/modz"
+)
+
+// inspection is the result of inspecting the dependency graph of a set of modules.
+type inspection struct {
+	*modz.Graph
+
+	// Unresolved lists the keys consumed by a module that no module produces, other than
+	// collections and keys with a default value.
+	Unresolved []unresolvedKey `json:"unresolved"`
+	// Cycles lists the circular dependencies between modules.
+	Cycles [][]cycleLink `json:"cycles"`
+}
+
+// unresolvedKey is a consumed key that no module produces.
+type unresolvedKey struct {
+	Key       string   `json:"key"`
+	Consumers []string `json:"consumers"`
+}
+
+// cycleLink is a link of a dependency cycle: Module consumes Key, produced by Producer, or
+// Module requires Producer if Key is empty.
+type cycleLink struct {
+	Module   string `json:"module"`
+	Key      string `json:"key,omitempty"`
+	Producer string `json:"producer"`
+}
+
+// inspect finds the unresolved keys and the dependency cycles of g.
+func inspect(g *modz.Graph) *inspection {
+	producers := make(map[string][]string)
+	consumers := make(map[string][]string)
+	waits := make(map[string][]string)
+	for _, e := range g.Edges {
+		switch e.Kind {
+		case modz.EdgeProduces:
+			producers[e.Key] = append(producers[e.Key], e.Module)
+		case modz.EdgeConsumes:
+			consumers[e.Key] = append(consumers[e.Key], e.Module)
+			waits[e.Module] = append(waits[e.Module], e.Key)
+		case modz.EdgeOptional, modz.EdgeDecorates:
+			waits[e.Module] = append(waits[e.Module], e.Key)
+		}
+	}
+
+	in := &inspection{Graph: g, Unresolved: []unresolvedKey{}, Cycles: [][]cycleLink{}}
+	for _, k := range g.Keys {
+		if len(consumers[k.ID]) > 0 && len(producers[k.ID]) == 0 && !k.Collection && !k.Default {
+			in.Unresolved = append(in.Unresolved, unresolvedKey{Key: k.ID, Consumers: consumers[k.ID]})
+		}
+	}
+
+	// Walk the modules depth first, from each module to the producers of the keys it waits for
+	// and to the modules it requires.
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+	state := make(map[string]int)
+	var path []cycleLink
+	requires := make(map[string][]string)
+	for _, m := range g.Modules {
+		requires[m.ID] = m.Requires
+	}
+	var visit func(module string)
+	follow := func(link cycleLink) {
+		switch state[link.Producer] {
+		case visiting:
+			start := slices.IndexFunc(path, func(l cycleLink) bool { return l.Module == link.Producer })
+			if start < 0 {
+				start = len(path)
+			}
+			in.Cycles = append(in.Cycles, append(slices.Clone(path[start:]), link))
+		case unvisited:
+			path = append(path, link)
+			visit(link.Producer)
+			path = path[:len(path)-1]
+		}
+	}
+	visit = func(module string) {
+		state[module] = visiting
+		for _, key := range waits[module] {
+			for _, producer := range producers[key] {
+				follow(cycleLink{Module: module, Key: key, Producer: producer})
+			}
+		}
+		for _, required := range requires[module] {
+			follow(cycleLink{Module: module, Producer: required})
+		}
+		state[module] = visited
+	}
+	for _, m := range g.Modules {
+		if state[m.ID] == unvisited {
+			visit(m.ID)
+		}
+	}
+	return in
+}
+
+// writers maps the output formats to the functions writing an inspection in that format.
+var writers = map[string]func(io.Writer, *inspection) error{
+	"text": writeText,
+	"json": writeJSON,
+	"dot":  writeDOT,
+}
+
+// writeText writes a human-readable report of in.
+func writeText(w io.Writer, in *inspection) error {
+	var sb strings.Builder
+
+	sb.WriteString("Modules:\n")
+	children := make(map[string][]string)
+	known := make(map[string]bool)
+	for _, m := range in.Modules {
+		known[m.ID] = true
+	}
+	var roots []string
+	for _, m := range in.Modules {
+		if m.Parent != "" && known[m.Parent] {
+			children[m.Parent] = append(children[m.Parent], m.ID)
+		} else {
+			roots = append(roots, m.ID)
+		}
+	}
+	var tree func(id string, depth int)
+	requires := make(map[string][]string)
+	for _, m := range in.Modules {
+		requires[m.ID] = m.Requires
+	}
+	tree = func(id string, depth int) {
+		fmt.Fprintf(&sb, "%s%s\n", strings.Repeat("  ", depth+1), id)
+		for _, r := range requires[id] {
+			fmt.Fprintf(&sb, "%s  requires %s\n", strings.Repeat("  ", depth+1), r)
+		}
+		for _, child := range children[id] {
+			tree(child, depth+1)
+		}
+	}
+	for _, id := range roots {
+		tree(id, 0)
+	}
+
+	sb.WriteString("Keys:\n")
+	for _, k := range in.Keys {
+		if k.Default {
+			fmt.Fprintf(&sb, "  %s (%s, has default)\n", k.ID, k.Type)
+		} else {
+			fmt.Fprintf(&sb, "  %s (%s)\n", k.ID, k.Type)
+		}
+		for _, kind := range []struct{ edge, label string }{
+			{modz.EdgeProduces, "produced by"},
+			{modz.EdgeConsumes, "consumed by"},
+			{modz.EdgeOptional, "optionally consumed by"},
+			{modz.EdgeDecorates, "decorated by"},
+		} {
+			for _, e := range in.Edges {
+				if e.Key == k.ID && e.Kind == kind.edge {
+					fmt.Fprintf(&sb, "    %s %s\n", kind.label, e.Module)
+				}
+			}
+		}
+	}
+
+	sb.WriteString("Unresolved keys:")
+	if len(in.Unresolved) == 0 {
+		sb.WriteString(" none")
+	}
+	sb.WriteString("\n")
+	for _, u := range in.Unresolved {
+		fmt.Fprintf(&sb, "  %s, consumed by %s\n", u.Key, strings.Join(u.Consumers, ", "))
+	}
+
+	sb.WriteString("Cycles:")
+	if len(in.Cycles) == 0 {
+		sb.WriteString(" none")
+	}
+	sb.WriteString("\n")
+	for _, cycle := range in.Cycles {
+		sb.WriteString("  ")
+		for i, link := range cycle {
+			if i > 0 {
+				sb.WriteString(", which ")
+			}
+			if link.Key == "" {
+				fmt.Fprintf(&sb, "%s requires %s", link.Module, link.Producer)
+				continue
+			}
+			fmt.Fprintf(&sb, "%s consumes %s produced by %s", link.Module, link.Key, link.Producer)
+		}
+		sb.WriteString("\n")
+	}
+
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
+
+// writeJSON writes in as indented JSON: the graph, as written by [modz.Graph.WriteJSON],
+// with the unresolved and cycles fields added.
+func writeJSON(w io.Writer, in *inspection) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(in)
+}
+
+// writeDOT writes the graph of in in Graphviz DOT format.
+func writeDOT(w io.Writer, in *inspection) error {
+	return in.WriteDOT(w)
+}
@@ -0,0 +1,133 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/goosz/modz"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestGraph() *modz.Graph {
+	return &modz.Graph{
+		Modules: []modz.GraphModule{
+			{ID: "app:a"},
+			{ID: "app:b"},
+			{ID: "app:c", Parent: "app:a"},
+		},
+		Keys: []modz.GraphKey{
+			{ID: "app:x", Type: "int"},
+			{ID: "app:y", Type: "string"},
+			{ID: "app:z", Type: "bool"},
+			{ID: "app:set", Type: "[]string", Collection: true},
+			{ID: "app:timeout", Type: "int", Default: true},
+		},
+		Edges: []modz.GraphEdge{
+			{Module: "app:a", Key: "app:x", Kind: modz.EdgeProduces},
+			{Module: "app:a", Key: "app:y", Kind: modz.EdgeOptional},
+			{Module: "app:b", Key: "app:y", Kind: modz.EdgeProduces},
+			{Module: "app:b", Key: "app:x", Kind: modz.EdgeConsumes},
+			{Module: "app:c", Key: "app:z", Kind: modz.EdgeConsumes},
+			{Module: "app:c", Key: "app:set", Kind: modz.EdgeConsumes},
+			{Module: "app:c", Key: "app:timeout", Kind: modz.EdgeConsumes},
+		},
+	}
+}
+
+func TestInspect(t *testing.T) {
+	in := inspect(newTestGraph())
+
+	require.Equal(t, []unresolvedKey{{Key: "app:z", Consumers: []string{"app:c"}}}, in.Unresolved)
+	require.Equal(t, [][]cycleLink{{
+		{Module: "app:a", Key: "app:y", Producer: "app:b"},
+		{Module: "app:b", Key: "app:x", Producer: "app:a"},
+	}}, in.Cycles)
+}
+
+func TestInspect_NoIssues(t *testing.T) {
+	in := inspect(&modz.Graph{
+		Modules: []modz.GraphModule{{ID: "app:a"}, {ID: "app:b"}},
+		Keys:    []modz.GraphKey{{ID: "app:x", Type: "int"}},
+		Edges: []modz.GraphEdge{
+			{Module: "app:a", Key: "app:x", Kind: modz.EdgeProduces},
+			{Module: "app:b", Key: "app:x", Kind: modz.EdgeConsumes},
+		},
+	})
+
+	require.Empty(t, in.Unresolved)
+	require.Empty(t, in.Cycles)
+}
+
+func TestInspect_Requires(t *testing.T) {
+	in := inspect(&modz.Graph{
+		Modules: []modz.GraphModule{
+			{ID: "app:a", Requires: []string{"app:b"}},
+			{ID: "app:b"},
+		},
+		Keys: []modz.GraphKey{{ID: "app:x", Type: "int"}},
+		Edges: []modz.GraphEdge{
+			{Module: "app:a", Key: "app:x", Kind: modz.EdgeProduces},
+			{Module: "app:b", Key: "app:x", Kind: modz.EdgeConsumes},
+		},
+	})
+
+	require.Equal(t, [][]cycleLink{{
+		{Module: "app:a", Producer: "app:b"},
+		{Module: "app:b", Key: "app:x", Producer: "app:a"},
+	}}, in.Cycles)
+
+	var buf bytes.Buffer
+	require.NoError(t, writeText(&buf, in))
+	require.Contains(t, buf.String(), "  app:a\n    requires app:b\n")
+	require.Contains(t, buf.String(), "  app:a requires app:b, which app:b consumes app:x produced by app:a\n")
+}
+
+func TestWriteText(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, writeText(&buf, inspect(newTestGraph())))
+
+	require.Equal(t, `Modules:
+  app:a
+    app:c
+  app:b
+Keys:
+  app:x (int)
+    produced by app:a
+    consumed by app:b
+  app:y (string)
+    produced by app:b
+    optionally consumed by app:a
+  app:z (bool)
+    consumed by app:c
+  app:set ([]string)
+    consumed by app:c
+  app:timeout (int, has default)
+    consumed by app:c
+Unresolved keys:
+  app:z, consumed by app:c
+Cycles:
+  app:a consumes app:y produced by app:b, which app:b consumes app:x produced by app:a
+`, buf.String())
+}
+
+func TestWriteJSON(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, writeJSON(&buf, inspect(newTestGraph())))
+
+	var decoded struct {
+		Modules    []modz.GraphModule `json:"modules"`
+		Unresolved []unresolvedKey    `json:"unresolved"`
+		Cycles     [][]cycleLink      `json:"cycles"`
+	}
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &decoded))
+	require.Len(t, decoded.Modules, 3)
+	require.Len(t, decoded.Unresolved, 1)
+	require.Len(t, decoded.Cycles, 1)
+}
+
+func TestWriteDOT(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, writeDOT(&buf, inspect(newTestGraph())))
+	require.Contains(t, buf.String(), "digraph modz {")
+}
@@ -0,0 +1,70 @@
+// Command modz inspects the wiring of the modules of a Go package without building them.
+//
+// The package must export a function returning its root modules, by default:
+//
+//	func Modules() []modz.Module
+//
+// modz generates a small program that passes these modules to [modz.NewAssembly], runs it
+// with go run from the current directory, and prints the resulting module tree, the modules
+// producing and consuming each data key, the consumed keys that no module produces, and the
+// dependency cycles between modules. Modules are not configured, so modules installed by
+// other modules during Build are not shown.
+//
+// Usage:
+//
+//	modz [-format text|json|dot] [-func Modules] [package]
+//
+// The package defaults to the one in the current directory; a package pattern, such as ./...,
+// must match a single package. The output is sorted, so that the wiring of two versions of
+// an application can be compared with diff.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+)
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdout, os.Stderr))
+}
+
+// run runs the command with the given arguments and returns its exit code.
+func run(args []string, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet("modz", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	format := flags.String("format", "text", "output format: text, json or dot")
+	funcName := flags.String("func", "Modules", "name of the exported function returning the root modules")
+	flags.Usage = func() {
+		fmt.Fprintln(stderr, "usage: modz [-format text|json|dot] [-func Modules] [package]")
+		flags.PrintDefaults()
+	}
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	if flags.NArg() > 1 {
+		flags.Usage()
+		return 2
+	}
+	write, ok := writers[*format]
+	if !ok {
+		fmt.Fprintf(stderr, "modz: unknown format %q\n", *format)
+		return 2
+	}
+	pkg := "."
+	if flags.NArg() == 1 {
+		pkg = flags.Arg(0)
+	}
+
+	g, err := loadGraph(pkg, *funcName)
+	if err != nil {
+		fmt.Fprintf(stderr, "modz: %v\n", err)
+		return 1
+	}
+	if err := write(stdout, inspect(g)); err != nil {
+		fmt.Fprintf(stderr, "modz: %v\n", err)
+		return 1
+	}
+	return 0
+}
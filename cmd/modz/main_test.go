@@ -0,0 +1,66 @@
+package main
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRun(t *testing.T) {
+	if testing.Short() {
+		t.Skip("runs the go command")
+	}
+	var stdout, stderr bytes.Buffer
+	code := run([]string{"./testdata/app"}, &stdout, &stderr)
+	require.Equal(t, 0, code, stderr.String())
+
+	out := stdout.String()
+	require.Contains(t, out, "github.com/goosz/modz/cmd/modz/testdata/app:token, consumed by github.com/goosz/modz/cmd/modz/testdata/app:server")
+	require.Contains(t, out, "github.com/goosz/modz/cmd/modz/testdata/app:cache consumes github.com/goosz/modz/cmd/modz/testdata/app:store")
+}
+
+func TestRun_UnknownFunction(t *testing.T) {
+	if testing.Short() {
+		t.Skip("runs the go command")
+	}
+	var stdout, stderr bytes.Buffer
+	code := run([]string{"-func", "Missing", "./testdata/app"}, &stdout, &stderr)
+	require.Equal(t, 1, code)
+	require.Contains(t, stderr.String(), "Missing")
+}
+
+func TestRun_UsageErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+		want string
+	}{
+		{name: "unknown format", args: []string{"-format", "yaml"}, want: `unknown format "yaml"`},
+		{name: "too many packages", args: []string{"a", "b"}, want: "usage: modz"},
+		{name: "unknown flag", args: []string{"-unknown"}, want: "flag provided but not defined"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+			require.Equal(t, 2, run(tt.args, &stdout, &stderr))
+			require.Contains(t, stderr.String(), tt.want)
+			require.Empty(t, stdout.String())
+		})
+	}
+}
+
+func TestRun_MultiplePackages(t *testing.T) {
+	if testing.Short() {
+		t.Skip("runs the go command")
+	}
+	var stdout, stderr bytes.Buffer
+	require.Equal(t, 1, run([]string{"github.com/goosz/modz/cmd/..."}, &stdout, &stderr))
+	require.Contains(t, stderr.String(), `package pattern "github.com/goosz/modz/cmd/..." matches 2 packages, must match exactly one`)
+}
+
+func TestRun_InvalidFunctionName(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	require.Equal(t, 1, run([]string{"-func", "modules", "./testdata/app"}, &stdout, &stderr))
+	require.Contains(t, stderr.String(), "must be an exported identifier")
+}
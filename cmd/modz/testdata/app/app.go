@@ -0,0 +1,46 @@
+// Package app is a sample application used to test the modz command. Its server consumes a
+// token that no module produces, and its cache and store modules depend on each other.
+package app
+
+import "github.com/goosz/modz"
+
+var (
+	Config = modz.NewData[string]("config")
+	Server = modz.NewData[int]("server")
+	Token  = modz.NewData[string]("token")
+	Cache  = modz.NewData[string]("cache")
+	Store  = modz.NewData[string]("store")
+)
+
+type configModule struct{}
+
+func (*configModule) Name() string                { return "config" }
+func (*configModule) Produces() modz.DataKeys     { return modz.Keys(Config) }
+func (*configModule) Consumes() modz.DataKeys     { return nil }
+func (*configModule) Configure(modz.Binder) error { return nil }
+
+type serverModule struct{}
+
+func (*serverModule) Name() string                { return "server" }
+func (*serverModule) Produces() modz.DataKeys     { return modz.Keys(Server) }
+func (*serverModule) Consumes() modz.DataKeys     { return modz.Keys(Config, Token) }
+func (*serverModule) Configure(modz.Binder) error { return nil }
+
+type cacheModule struct{}
+
+func (*cacheModule) Name() string                { return "cache" }
+func (*cacheModule) Produces() modz.DataKeys     { return modz.Keys(Cache) }
+func (*cacheModule) Consumes() modz.DataKeys     { return modz.Keys(Store) }
+func (*cacheModule) Configure(modz.Binder) error { return nil }
+
+type storeModule struct{}
+
+func (*storeModule) Name() string                { return "store" }
+func (*storeModule) Produces() modz.DataKeys     { return modz.Keys(Store) }
+func (*storeModule) Consumes() modz.DataKeys     { return modz.Keys(Cache) }
+func (*storeModule) Configure(modz.Binder) error { return nil }
+
+// Modules returns the root modules of the application.
+func Modules() []modz.Module {
+	return []modz.Module{&serverModule{}, &configModule{}, &cacheModule{}, &storeModule{}}
+}
@@ -0,0 +1,18 @@
+// Command modzcheck checks that modz modules declare the data keys used by their Configure
+// methods in Produces, Consumes and OptionalConsumes. See package
+// [github.com/goosz/modz/modzcheck] for the checks it performs.
+//
+// It can be run on its own, or as a go vet tool:
+//
+//	modzcheck ./...
+//	go vet -vettool=$(which modzcheck) ./...
+package main
+
+import (
+	"github.com/goosz/modz/modzcheck"
+	"golang.org/x/tools/go/analysis/singlechecker"
+)
+
+func main() {
+	singlechecker.Main(modzcheck.Analyzer)
+}
@@ -0,0 +1,316 @@
+package modz
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/goosz/commonz"
+)
+
+// SetData is a collection-valued [DataKey] to which many modules can contribute elements.
+//
+// Unlike a [Data] key, which has exactly one producer, any number of modules may declare a
+// SetData key in Produces() and Add() elements to it during configuration; a contributing
+// module may also add no element at all. Modules that declare the key in Consumes() are
+// configured only after every contributor has been configured, and Get() returns all the
+// contributed elements.
+//
+// The elements are ordered by the signature of the contributing module, then in the order each
+// module added them, so the same assembly always yields the same slice. Duplicate elements are
+// kept.
+//
+// Always use [NewSetData] to create new SetData keys.
+type SetData[T any] interface {
+	DataKey
+
+	// Get retrieves all the elements contributed to this key from the provided DataReader.
+	Get(DataReader) ([]T, error)
+
+	// Add contributes an element to this key through the provided DataWriter.
+	Add(DataWriter, T) error
+}
+
+// MapData is a collection-valued [DataKey] to which many modules can contribute entries.
+//
+// MapData follows the same rules as [SetData], except that contributions are key/value
+// entries and Get() returns them as a map. Contributing the same map key twice, from the same
+// module or from different modules, is an error reported to the second contributor.
+//
+// Always use [NewMapData] to create new MapData keys.
+type MapData[K comparable, V any] interface {
+	DataKey
+
+	// Get retrieves all the entries contributed to this key from the provided DataReader.
+	Get(DataReader) (map[K]V, error)
+
+	// Put contributes an entry to this key through the provided DataWriter.
+	Put(DataWriter, K, V) error
+}
+
+// collectionKey is implemented by collection-valued keys, whose value is aggregated from the
+// contributions of many modules instead of being Put by a single producer.
+type collectionKey interface {
+	DataKey
+
+	// checkContribution returns an error if value cannot be added to the given contributions.
+	checkContribution(contributions []any, value any) error
+
+	// aggregate builds the value of the collection from its ordered contributions.
+	aggregate(contributions []any) any
+}
+
+// contribution is a value contributed to a collection key by a module.
+type contribution struct {
+	moduleID string
+	value    any
+}
+
+// setDataKey is the concrete implementation of the SetData interface.
+type setDataKey[T any] struct {
+	dataKeySignature dataKeySignature
+	serial           uint64
+}
+
+// Ensure that *setDataKey[T] implements SetData[T] and collectionKey.
+var _ SetData[any] = (*setDataKey[any])(nil)
+var _ collectionKey = (*setDataKey[any])(nil)
+
+func (d *setDataKey[T]) Get(r DataReader) ([]T, error) {
+	if r == nil {
+		return nil, newNilAccessorError(d, "data reader Get")
+	}
+	val, err := r.getData(d)
+	if err != nil {
+		return nil, err
+	}
+	typedVal, ok := val.([]T)
+	if !ok {
+		return nil, newDataOperationError(ErrTypeMismatch, d, fmt.Sprintf("type assertion failed: expected %T, got %T", typedVal, val))
+	}
+	return typedVal, nil
+}
+
+func (d *setDataKey[T]) Add(w DataWriter, t T) error {
+	if w == nil {
+		return newNilAccessorError(d, "data writer Add")
+	}
+	return w.putData(d, t)
+}
+
+func (d *setDataKey[T]) checkContribution(_ []any, value any) error {
+	if _, ok := value.(T); !ok {
+		var zero T
+		return newDataOperationError(ErrTypeMismatch, d, fmt.Sprintf("type assertion failed: expected %T, got %T", zero, value))
+	}
+	return nil
+}
+
+func (d *setDataKey[T]) aggregate(contributions []any) any {
+	elements := make([]T, 0, len(contributions))
+	for _, c := range contributions {
+		elements = append(elements, c.(T))
+	}
+	return elements
+}
+
+func (d *setDataKey[T]) signature() dataKeySignature {
+	return d.dataKeySignature
+}
+
+func (d *setDataKey[T]) String() string {
+	return fmt.Sprintf("SetData[%s](%s#%d)", d.typeName(), d.signature(), d.serial)
+}
+
+// typeName returns the name of the Go type stored under this key.
+func (d *setDataKey[T]) typeName() string {
+	return commonz.TypeName(reflect.TypeFor[[]T]())
+}
+
+// mapEntry is a single entry contributed to a MapData key.
+type mapEntry[K comparable, V any] struct {
+	key   K
+	value V
+}
+
+// mapDataKey is the concrete implementation of the MapData interface.
+type mapDataKey[K comparable, V any] struct {
+	dataKeySignature dataKeySignature
+	serial           uint64
+}
+
+// Ensure that *mapDataKey[K, V] implements MapData[K, V] and collectionKey.
+var _ MapData[string, any] = (*mapDataKey[string, any])(nil)
+var _ collectionKey = (*mapDataKey[string, any])(nil)
+
+func (d *mapDataKey[K, V]) Get(r DataReader) (map[K]V, error) {
+	if r == nil {
+		return nil, newNilAccessorError(d, "data reader Get")
+	}
+	val, err := r.getData(d)
+	if err != nil {
+		return nil, err
+	}
+	typedVal, ok := val.(map[K]V)
+	if !ok {
+		return nil, newDataOperationError(ErrTypeMismatch, d, fmt.Sprintf("type assertion failed: expected %T, got %T", typedVal, val))
+	}
+	return typedVal, nil
+}
+
+func (d *mapDataKey[K, V]) Put(w DataWriter, k K, v V) error {
+	if w == nil {
+		return newNilAccessorError(d, "data writer Put")
+	}
+	return w.putData(d, mapEntry[K, V]{key: k, value: v})
+}
+
+func (d *mapDataKey[K, V]) checkContribution(contributions []any, value any) error {
+	entry, ok := value.(mapEntry[K, V])
+	if !ok {
+		return newDataOperationError(ErrTypeMismatch, d, fmt.Sprintf("type assertion failed: expected %T, got %T", entry, value))
+	}
+	for _, c := range contributions {
+		if c.(mapEntry[K, V]).key == entry.key {
+			return newDataOperationError(ErrAlreadySet, d, fmt.Sprintf("map key '%v' already set", entry.key))
+		}
+	}
+	return nil
+}
+
+func (d *mapDataKey[K, V]) aggregate(contributions []any) any {
+	entries := make(map[K]V, len(contributions))
+	for _, c := range contributions {
+		entry := c.(mapEntry[K, V])
+		entries[entry.key] = entry.value
+	}
+	return entries
+}
+
+func (d *mapDataKey[K, V]) signature() dataKeySignature {
+	return d.dataKeySignature
+}
+
+func (d *mapDataKey[K, V]) String() string {
+	return fmt.Sprintf("MapData[%s](%s#%d)", d.typeName(), d.signature(), d.serial)
+}
+
+// typeName returns the name of the Go type stored under this key.
+func (d *mapDataKey[K, V]) typeName() string {
+	return commonz.TypeName(reflect.TypeFor[map[K]V]())
+}
+
+// NewSetData creates a new [SetData] key collecting elements of type T from many modules.
+//
+// Like [NewData], it must be called from package-level var declarations only, and it panics
+// otherwise.
+//
+// During Build(), a key that modules are waiting for is assembled as soon as its contributors
+// have all been configured, before the keys without a producer are resolved as absent,
+// inherited from a parent assembly or set to their default value (see [OptionalConsumer],
+// [WithParent] and [WithDefault]). A module only configured once such a key is resolved
+// therefore cannot install a contributor to the collection: the installation fails with
+// [ErrAlreadySet].
+func NewSetData[T any](name string) SetData[T] {
+	sig, serial := newDataKeyIdentity("NewSetData", name)
+	return &setDataKey[T]{
+		dataKeySignature: sig,
+		serial:           serial,
+	}
+}
+
+// NewMapData creates a new [MapData] key collecting entries of type K and V from many modules.
+//
+// Like [NewData], it must be called from package-level var declarations only, and it panics
+// otherwise. Like a [SetData] key, it is assembled before the keys without a producer are
+// resolved, and cannot be contributed to by a module waiting for them (see [NewSetData]).
+func NewMapData[K comparable, V any](name string) MapData[K, V] {
+	sig, serial := newDataKeyIdentity("NewMapData", name)
+	return &mapDataKey[K, V]{
+		dataKeySignature: sig,
+		serial:           serial,
+	}
+}
+
+// contributeDataValue records a value contributed by the module bound to b to a collection key.
+// This is used internally by the binder.
+func (a *assembly) contributeDataValue(b *binder, key collectionKey, value any) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if _, exists := a.data[key]; exists {
+		return newDataOperationError(ErrAlreadySet, key, "collection has already been assembled")
+	}
+	values := make([]any, len(a.contributions[key]))
+	for i, c := range a.contributions[key] {
+		values[i] = c.value
+	}
+	if err := key.checkContribution(values, value); err != nil {
+		return err
+	}
+	a.contributions[key] = append(a.contributions[key], contribution{
+		moduleID: b.moduleSignature.String(),
+		value:    value,
+	})
+	return nil
+}
+
+// finalizeCollections assembles the value of a collection key whose contributors have all
+// been configured successfully, and notifies the modules waiting for it. It must only be
+// called when no module is being configured, so that no contribution is in flight.
+//
+// Unless unconsumed is true, only the keys modules are waiting for are assembled, and
+// finalizeCollections returns as soon as a module becomes ready, since that module may still
+// contribute to the others. Otherwise, the keys no module waits for are assembled too.
+//
+// Returns true if any module became ready.
+//
+// The caller must hold a.mu.
+func (a *assembly) finalizeCollections(unconsumed bool) bool {
+	keys := make(map[DataKey]struct{})
+	if unconsumed {
+		for k := range a.contributors {
+			keys[k] = struct{}{}
+		}
+	}
+	for k := range a.waiters {
+		if _, ok := k.(collectionKey); ok {
+			keys[k] = struct{}{}
+		}
+	}
+	for _, k := range sortedKeys(keys) {
+		if _, exists := a.data[k]; exists {
+			continue
+		}
+		if len(a.contributors[k]) == 0 && a.parentHas(k) {
+			// Nothing to add to the parent's collection: settle inherits it.
+			continue
+		}
+		complete := true
+		for _, c := range a.contributors[k] {
+			if !c.configured.Load() || c.failed.Load() {
+				complete = false
+				break
+			}
+		}
+		if !complete {
+			continue
+		}
+		// Order contributions by module signature, keeping each module's own order, after
+		// those inherited from the parent assembly.
+		contributions := slices.Clone(a.contributions[k])
+		slices.SortStableFunc(contributions, func(x, y contribution) int {
+			return strings.Compare(x.moduleID, y.moduleID)
+		})
+		contributions = slices.Concat(a.inheritedContributions(k), contributions)
+		a.contributions[k] = contributions
+		values := make([]any, len(contributions))
+		for i, c := range contributions {
+			values[i] = c.value
+		}
+		if a.storeDataValue(k, k.(collectionKey).aggregate(values)) {
+			return true
+		}
+	}
+	return false
+}
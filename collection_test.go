@@ -0,0 +1,246 @@
+package modz
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSetData_Contributions(t *testing.T) {
+	var got []string
+	consumer := &MockModule{
+		NameValue:     "consumer",
+		ConsumesValue: Keys(ListKey),
+		ConfigureFunc: func(b Binder) error {
+			var err error
+			got, err = ListKey.Get(b)
+			return err
+		},
+	}
+	b := &MockModule{
+		NameValue:     "b",
+		ProducesValue: Keys(ListKey),
+		ConfigureFunc: func(b Binder) error {
+			if err := ListKey.Add(b, "b1"); err != nil {
+				return err
+			}
+			return ListKey.Add(b, "b2")
+		},
+	}
+	a := &MockModule{
+		NameValue:     "a",
+		ProducesValue: Keys(ListKey),
+		ConfigureFunc: func(b Binder) error { return ListKey.Add(b, "a1") },
+	}
+	silent := &MockModule{NameValue: "silent", ProducesValue: Keys(ListKey)}
+
+	asm, err := NewAssembly(consumer, b, silent, a)
+	require.NoError(t, err)
+	require.NoError(t, asm.Build())
+	require.Equal(t, []string{"a1", "b1", "b2"}, got)
+
+	values, err := ListKey.Get(asm)
+	require.NoError(t, err)
+	require.Equal(t, []string{"a1", "b1", "b2"}, values)
+}
+
+func TestSetData_Empty(t *testing.T) {
+	var got []string
+	consumer := &MockModule{
+		NameValue:     "consumer",
+		ConsumesValue: Keys(ListKey),
+		ConfigureFunc: func(b Binder) error {
+			var err error
+			got, err = ListKey.Get(b)
+			return err
+		},
+	}
+	asm, err := NewAssembly(consumer)
+	require.NoError(t, err)
+	require.NoError(t, asm.Build())
+	require.NotNil(t, got)
+	require.Empty(t, got)
+}
+
+func TestSetData_DynamicContributor(t *testing.T) {
+	// installer adds a contributor during configuration; the consumer must wait for it too.
+	var got []string
+	late := &MockModule{
+		NameValue:     "late",
+		ProducesValue: Keys(ListKey),
+		ConsumesValue: Keys(FooKey),
+		ConfigureFunc: func(b Binder) error { return ListKey.Add(b, "late") },
+	}
+	installer := &MockModule{
+		NameValue:     "installer",
+		ProducesValue: Keys(FooKey, ListKey),
+		ConfigureFunc: func(b Binder) error {
+			if err := b.Install(late); err != nil {
+				return err
+			}
+			if err := ListKey.Add(b, "installer"); err != nil {
+				return err
+			}
+			return FooKey.Put(b, 1)
+		},
+	}
+	consumer := &MockModule{
+		NameValue:     "consumer",
+		ConsumesValue: Keys(ListKey),
+		ConfigureFunc: func(b Binder) error {
+			var err error
+			got, err = ListKey.Get(b)
+			return err
+		},
+	}
+	asm, err := NewAssembly(consumer, installer)
+	require.NoError(t, err)
+	require.NoError(t, asm.Build())
+	require.Equal(t, []string{"installer", "late"}, got)
+}
+
+func TestSetData_ContributionAfterAssembly(t *testing.T) {
+	// consumer installs a new contributor after the collection has been assembled.
+	late := &MockModule{NameValue: "late", ProducesValue: Keys(ListKey)}
+	consumer := &MockModule{
+		NameValue:     "consumer",
+		ConsumesValue: Keys(ListKey),
+		ConfigureFunc: func(b Binder) error { return b.Install(late) },
+	}
+	asm, err := NewAssembly(consumer)
+	require.NoError(t, err)
+
+	err = asm.Build()
+	require.ErrorIs(t, err, ErrAlreadySet)
+	require.Contains(t, err.Error(), "already been assembled")
+}
+
+func TestSetData_ContributorInstalledAfterSettling(t *testing.T) {
+	// The installer only becomes ready once settle resolves FooKey as absent or RetriesKey to
+	// its default, after ListKey has been assembled for consumer: the contributor it installs
+	// comes too late.
+	for name, declare := range map[string]func(m *MockModule){
+		"optional": func(m *MockModule) { m.OptionalConsumesValue = Keys(FooKey) },
+		"default":  func(m *MockModule) { m.ConsumesValue = Keys(RetriesKey) },
+	} {
+		t.Run(name, func(t *testing.T) {
+			contributor := &MockModule{
+				NameValue:     "contributor",
+				ProducesValue: Keys(ListKey),
+				ConfigureFunc: func(b Binder) error { return ListKey.Add(b, "late") },
+			}
+			installer := &MockModule{
+				NameValue:     "installer",
+				ConfigureFunc: func(b Binder) error { return b.Install(contributor) },
+			}
+			declare(installer)
+			consumer := &MockModule{NameValue: "consumer", ConsumesValue: Keys(ListKey)}
+			asm, err := NewAssembly(consumer, installer)
+			require.NoError(t, err)
+
+			err = asm.Build()
+			require.ErrorIs(t, err, ErrAlreadySet)
+			require.ErrorContains(t, err, "cannot contribute to a collection that has already been assembled")
+		})
+	}
+}
+
+func TestSetData_ContributorInstalledByConsumerOfAnotherCollection(t *testing.T) {
+	// first consumes ListKey and installs a contributor to TagsKey, which second consumes:
+	// TagsKey must not be assembled before first has been configured.
+	var got []string
+	tagger := &MockModule{
+		NameValue:     "tagger",
+		ProducesValue: Keys(TagsKey),
+		ConfigureFunc: func(b Binder) error { return TagsKey.Add(b, "tag") },
+	}
+	first := &MockModule{
+		NameValue:     "first",
+		ConsumesValue: Keys(ListKey),
+		ConfigureFunc: func(b Binder) error { return b.Install(tagger) },
+	}
+	second := &MockModule{
+		NameValue:     "second",
+		ConsumesValue: Keys(TagsKey),
+		ConfigureFunc: func(b Binder) error {
+			var err error
+			got, err = TagsKey.Get(b)
+			return err
+		},
+	}
+	asm, err := NewAssembly(first, second)
+	require.NoError(t, err)
+	require.NoError(t, asm.Build())
+	require.Equal(t, []string{"tag"}, got)
+}
+
+func TestSetData_FailedContributor(t *testing.T) {
+	failing := &MockModule{
+		NameValue:     "failing",
+		ProducesValue: Keys(ListKey),
+		ConfigureFunc: func(b Binder) error { return errors.New("failure") },
+	}
+	consumer := &MockModule{NameValue: "consumer", ConsumesValue: Keys(ListKey)}
+	asm, err := NewAssemblyWithOptions([]AssemblyOption{WithAggregateErrors()}, failing, consumer)
+	require.NoError(t, err)
+
+	err = asm.Build()
+	var asmErr *AssemblyError
+	require.ErrorAs(t, err, &asmErr)
+	require.Len(t, asmErr.Errors, 1)
+	require.Equal(t, []string{"github.com/goosz/modz:consumer"}, asmErr.Skipped)
+}
+
+func TestMapData_Contributions(t *testing.T) {
+	a := &MockModule{
+		NameValue:     "a",
+		ProducesValue: Keys(IndexKey),
+		ConfigureFunc: func(b Binder) error { return IndexKey.Put(b, "a", 1) },
+	}
+	b := &MockModule{
+		NameValue:     "b",
+		ProducesValue: Keys(IndexKey),
+		ConfigureFunc: func(b Binder) error { return IndexKey.Put(b, "b", 2) },
+	}
+	asm, err := NewAssembly(a, b)
+	require.NoError(t, err)
+	require.NoError(t, asm.Build())
+
+	values, err := IndexKey.Get(asm)
+	require.NoError(t, err)
+	require.Equal(t, map[string]int{"a": 1, "b": 2}, values)
+}
+
+func TestMapData_DuplicateKey(t *testing.T) {
+	a := &MockModule{
+		NameValue:     "a",
+		ProducesValue: Keys(IndexKey),
+		ConfigureFunc: func(b Binder) error { return IndexKey.Put(b, "same", 1) },
+	}
+	b := &MockModule{
+		NameValue:     "b",
+		ProducesValue: Keys(IndexKey),
+		ConfigureFunc: func(b Binder) error { return IndexKey.Put(b, "same", 2) },
+	}
+	asm, err := NewAssembly(a, b)
+	require.NoError(t, err)
+
+	err = asm.Build()
+	require.ErrorIs(t, err, ErrAlreadySet)
+	require.Contains(t, err.Error(), "map key 'same' already set")
+	var configErr *ConfigurationError
+	require.ErrorAs(t, err, &configErr)
+	require.Equal(t, "github.com/goosz/modz:b", configErr.ModuleID)
+}
+
+func TestCollectionKeys_String(t *testing.T) {
+	require.Contains(t, fmt.Sprint(ListKey), "SetData[[]string](github.com/goosz/modz:list#")
+	require.Contains(t, fmt.Sprint(IndexKey), "MapData[map[string]int](github.com/goosz/modz:index#")
+}
+
+func TestNewSetData_PanicsOutsidePackageVar(t *testing.T) {
+	require.Panics(t, func() { NewSetData[int]("local") })
+	require.Panics(t, func() { NewMapData[string, int]("local") })
+}
@@ -1,8 +1,10 @@
 package modz
 
 import (
+	"errors"
 	"fmt"
 	"reflect"
+	"sync"
 	"sync/atomic"
 
 	"github.com/goosz/commonz"
@@ -52,9 +54,31 @@ type Data[T any] interface {
 	// Returns an error if the value is not available or if there is a type mismatch.
 	Get(DataReader) (T, error)
 
+	// Lookup retrieves the value of type T stored under this Data key in the provided DataReader,
+	// reporting whether it is present. Unlike Get(), an absent value is not an error: Lookup
+	// returns the zero value and false. This is how modules read keys declared in
+	// [OptionalConsumer].OptionalConsumes().
+	//
+	// Returns an error for any other failure, such as a type mismatch or an undeclared key.
+	Lookup(DataReader) (T, bool, error)
+
 	// Put stores a value of type T under this Data key in the provided DataWriter.
 	// Returns an error if the DataWriter is nil or if the value cannot be stored.
 	Put(DataWriter, T) error
+
+	// Provide stores a constructor for the value of type T under this Data key in the provided
+	// DataWriter, instead of the value itself. It satisfies the key like Put() does, but the
+	// constructor is only called on the first Get() of the value, whether from a consumer's
+	// [Binder] or from the built [Assembly], and exactly once even if Get() is called
+	// concurrently. Its result, value or error, is returned to every caller.
+	//
+	// An error from the constructor is returned as a [ConfigurationError] naming the
+	// producing module, with the Operation "Provide". The constructor runs after the
+	// producer's Configure() has returned, so it must not use the producer's [Binder]; it
+	// should capture the values it needs during Configure() instead.
+	//
+	// Returns an error if the DataWriter or the constructor is nil, or if the value cannot be stored.
+	Provide(DataWriter, func() (T, error)) error
 }
 
 // DataKey is a type-erased identifier for a [Data] instance.
@@ -85,17 +109,78 @@ func (s dataKeySignature) String() string {
 type dataKey[T any] struct {
 	dataKeySignature dataKeySignature
 	serial           uint64
+	validators       []func(T) error
+	// makeDefault returns the value stored under the key when no module produces it, if set.
+	makeDefault func() any
 }
 
-// Ensure that *dataKey[T] implements Data[T].
+// Ensure that *dataKey[T] implements Data[T], validatedKey and defaultedKey.
 var _ Data[any] = (*dataKey[any])(nil)
+var _ validatedKey = (*dataKey[any])(nil)
+var _ defaultedKey = (*dataKey[any])(nil)
+
+// validatedKey is implemented by keys that check the values stored under them.
+type validatedKey interface {
+	DataKey
+
+	// validate returns an error if value is rejected by a validator of the key. A value
+	// provided lazily is not validated: it is validated once computed.
+	validate(value any) error
+}
+
+// defaultedKey is implemented by keys that may have a default value.
+type defaultedKey interface {
+	DataKey
+
+	// newDefault returns the default value of the key, and false if it has none. A default
+	// computed by a function is returned as a lazyValue.
+	newDefault() (any, bool)
+}
+
+// hasDefault reports whether k has a default value, see [WithDefault].
+func hasDefault(k DataKey) bool {
+	dk, ok := k.(defaultedKey)
+	if !ok {
+		return false
+	}
+	_, ok = dk.newDefault()
+	return ok
+}
+
+// DataOption configures a [Data] key created with [NewData].
+type DataOption[T any] func(*dataKey[T])
+
+// WithValidator adds a validator to a [Data] key. The validator is called with every value
+// stored under the key, before it is stored, and returns an error to reject it:
+//
+//	var Port = modz.NewData("port", modz.WithValidator(func(port int) error {
+//		if port <= 0 || port > 65535 {
+//			return fmt.Errorf("port %d out of range", port)
+//		}
+//		return nil
+//	}))
+//
+// A value rejected when a module stores it with Put() is reported as a [ConfigurationError] on
+// the module, wrapping an error that matches [ErrInvalidValue] and names the key. A value
+// stored with Provide() is validated when it is computed, a value seeded with [WithData] when
+// the [Assembly] is created, and a value stored in a [DataStore] when it is stored.
+//
+// Validators run in the order they are given; the first error stops the validation.
+func WithValidator[T any](validator func(T) error) DataOption[T] {
+	return func(d *dataKey[T]) {
+		if validator == nil {
+			panic(fmt.Sprintf("WithValidator: validator for data key '%s' must not be nil", d))
+		}
+		d.validators = append(d.validators, validator)
+	}
+}
 
 // Global counter for generating unique serial numbers
 var dataKeySerialCounter atomic.Uint64
 
 func (d *dataKey[T]) Get(r DataReader) (T, error) {
 	if r == nil {
-		return commonz.Zero[T](), fmt.Errorf("data reader Get: is nil")
+		return commonz.Zero[T](), newNilAccessorError(d, "data reader Get")
 	}
 	val, err := r.getData(d)
 	if err != nil {
@@ -104,25 +189,157 @@ func (d *dataKey[T]) Get(r DataReader) (T, error) {
 	typedVal, ok := val.(T)
 	if !ok {
 		var zero T
-		return zero, fmt.Errorf("data key '%s': type assertion failed: expected %T, got %T", d, zero, val)
+		return zero, newDataOperationError(ErrTypeMismatch, d, fmt.Sprintf("type assertion failed: expected %T, got %T", zero, val))
 	}
 	return typedVal, nil
 }
 
+func (d *dataKey[T]) Lookup(r DataReader) (T, bool, error) {
+	val, err := d.Get(r)
+	if errors.Is(err, ErrNotProduced) {
+		return commonz.Zero[T](), false, nil
+	}
+	if err != nil {
+		return commonz.Zero[T](), false, err
+	}
+	return val, true, nil
+}
+
 func (d *dataKey[T]) Put(w DataWriter, t T) error {
 	if w == nil {
-		return fmt.Errorf("data writer Put: is nil")
+		return newNilAccessorError(d, "data writer Put")
 	}
 	return w.putData(d, t)
 }
 
+func (d *dataKey[T]) Provide(w DataWriter, provide func() (T, error)) error {
+	if w == nil {
+		return newNilAccessorError(d, "data writer Provide")
+	}
+	if provide == nil {
+		return newDataOperationError(ErrInvalidArgument, d, "cannot provide data with nil constructor")
+	}
+	return w.putData(d, d.newLazyValue(provide))
+}
+
+// newLazyValue returns a lazyValue computing the value of the key with provide, and
+// validating it.
+func (d *dataKey[T]) newLazyValue(provide func() (T, error)) *lazyValue {
+	return &lazyValue{
+		provide: func() (any, error) {
+			val, err := provide()
+			if err != nil {
+				return nil, err
+			}
+			if err := d.validate(val); err != nil {
+				return nil, err
+			}
+			return val, nil
+		},
+	}
+}
+
+// setDefault sets the function returning the default value of the key. It panics if the key
+// already has a default.
+func (d *dataKey[T]) setDefault(makeDefault func() any) {
+	if d.makeDefault != nil {
+		panic(fmt.Sprintf("data key '%s' has more than one default", d))
+	}
+	d.makeDefault = makeDefault
+}
+
+func (d *dataKey[T]) newDefault() (any, bool) {
+	if d.makeDefault == nil {
+		return nil, false
+	}
+	return d.makeDefault(), true
+}
+
+func (d *dataKey[T]) validate(value any) error {
+	if _, lazy := value.(*lazyValue); lazy {
+		// Validated by the constructor registered with Provide.
+		return nil
+	}
+	typedVal, ok := value.(T)
+	if !ok {
+		// Reported as a type mismatch when the value is read.
+		return nil
+	}
+	for _, validator := range d.validators {
+		if err := validator(typedVal); err != nil {
+			return newValidationError(d, err)
+		}
+	}
+	return nil
+}
+
 func (d *dataKey[T]) signature() dataKeySignature {
 	return d.dataKeySignature
 }
 
 func (d *dataKey[T]) String() string {
-	var zero T
-	return fmt.Sprintf("Data[%s](%s#%d)", commonz.TypeName(reflect.TypeOf(zero)), d.signature(), d.serial)
+	return fmt.Sprintf("Data[%s](%s#%d)", d.typeName(), d.signature(), d.serial)
+}
+
+// typeName returns the name of the Go type T stored under this key.
+func (d *dataKey[T]) typeName() string {
+	return commonz.TypeName(reflect.TypeFor[T]())
+}
+
+// lazyValue is a value stored with Provide, computed on first access.
+type lazyValue struct {
+	// moduleID is the signature of the producing module, set when the value is stored by a
+	// module; it is empty for a value stored in a DataStore.
+	moduleID string
+	provide  func() (any, error)
+
+	once  sync.Once
+	value any
+	err   error
+}
+
+// get returns the value, calling the constructor on the first call only.
+func (v *lazyValue) get() (any, error) {
+	v.once.Do(func() {
+		v.value, v.err = v.provide()
+		if v.err != nil && v.moduleID != "" {
+			v.err = &ConfigurationError{ModuleID: v.moduleID, Operation: "Provide", Err: v.err}
+		}
+		v.provide = nil
+	})
+	return v.value, v.err
+}
+
+// WithDefault sets the default value of a [Data] key, used when no module produces the key:
+//
+//	var Timeout = modz.NewData("timeout", modz.WithDefault(30*time.Second))
+//
+// During Build(), once no further module can be installed, every key consumed by a waiting
+// module and without a producer is satisfied with its default value, whether it is consumed
+// or optionally consumed. A key whose producer fails is not defaulted, and a module installed
+// afterwards can no longer produce a defaulted key. The defaulted keys are listed in the
+// [BuildReport] and flagged in [DataEntry].
+//
+// The default value must be accepted by the validators of the key, if any (see
+// [WithValidator]); [NewData] panics otherwise. A key has at most one default, set with
+// WithDefault or [WithDefaultFunc].
+func WithDefault[T any](value T) DataOption[T] {
+	return func(d *dataKey[T]) {
+		d.setDefault(func() any { return value })
+	}
+}
+
+// WithDefaultFunc is like [WithDefault], but the default value is computed by provide, as if
+// it was stored with [Data].Provide(): provide is called on the first Get() of the default,
+// by each [Assembly] using it. Its value is checked by the validators of the key then, and an
+// error from provide or a validator is returned by Get().
+func WithDefaultFunc[T any](provide func() (T, error)) DataOption[T] {
+	return func(d *dataKey[T]) {
+		if provide == nil {
+			panic(fmt.Sprintf("WithDefaultFunc: constructor for data key '%s' must not be nil", d))
+		}
+		d.setDefault(func() any { return d.newLazyValue(provide) })
+	}
 }
 
 // NewData creates a new [Data] instance for managing data of type T.
@@ -134,23 +351,44 @@ func (d *dataKey[T]) String() string {
 // The returned Data key includes package information and a process-unique serial number
 // for enhanced identity and debugging.
 //
+// Options such as [WithValidator] customize the key.
+//
 // **Important:** This function must be called from package-level var declarations only.
 // It will panic if called from functions, methods, or any other context. This ensures
 // proper initialization and prevents runtime conflicts.
-func NewData[T any](name string) Data[T] {
-	caller := commonz.GetCaller(commonz.ParentCaller)
+func NewData[T any](name string, opts ...DataOption[T]) Data[T] {
+	sig, serial := newDataKeyIdentity("NewData", name)
+	d := &dataKey[T]{
+		dataKeySignature: sig,
+		serial:           serial,
+	}
+	for _, opt := range opts {
+		opt(d)
+	}
+	if value, ok := d.newDefault(); ok {
+		if _, lazy := value.(*lazyValue); !lazy {
+			if err := d.validate(value); err != nil {
+				panic(fmt.Sprintf("NewData: invalid default for data key '%s': %v", d, err))
+			}
+		}
+	}
+	return d
+}
+
+// newDataKeyIdentity returns the signature and serial number for a new data key named name.
+// It must be called directly by the exported constructor, whose caller must be
+// a package-level var declaration; it panics otherwise.
+func newDataKeyIdentity(constructor string, name string) (dataKeySignature, uint64) {
+	caller := commonz.GetCaller(commonz.GrandparentCaller)
 
 	if caller.Function != "init" {
-		panic(fmt.Sprintf("NewData must be called from package-level var declarations, not from %s.%s", caller.Package, caller.Function))
+		panic(fmt.Sprintf("%s must be called from package-level var declarations, not from %s.%s", constructor, caller.Package, caller.Function))
 	}
 
 	serial := dataKeySerialCounter.Add(1)
 
-	return &dataKey[T]{
-		dataKeySignature: dataKeySignature{
-			name: name,
-			pkg:  caller.Package,
-		},
-		serial: serial,
-	}
+	return dataKeySignature{
+		name: name,
+		pkg:  caller.Package,
+	}, serial
 }
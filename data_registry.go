@@ -2,6 +2,7 @@ package modz
 
 import (
 	"fmt"
+	"maps"
 	"sync"
 )
 
@@ -20,6 +21,15 @@ func newDataRegistry() *dataRegistry {
 	}
 }
 
+// clone returns a new registry holding the same keys, which can then be extended independently.
+func (r *dataRegistry) clone() *dataRegistry {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return &dataRegistry{
+		store: maps.Clone(r.store),
+	}
+}
+
 // Validate checks if a DataKey is valid for its signature.
 // If the signature is being seen for the first time, it's valid.
 // Returns an error if the signature is already registered with a different DataKey.
@@ -30,7 +40,11 @@ func (r *dataRegistry) Validate(key DataKey) error {
 	sig := key.signature()
 	if existing, exists := r.store[sig]; exists {
 		if existing != key {
-			return fmt.Errorf("data key signature clash: '%s' conflicts with existing key '%s'", sig, existing)
+			return &Error{
+				Kind: ErrSignatureClash,
+				Key:  key,
+				msg:  fmt.Sprintf("data key signature clash: '%s' conflicts with existing key '%s'", sig, existing),
+			}
 		}
 		// Same key, no error
 		return nil
@@ -1,7 +1,10 @@
 package modz_test
 
 import (
+	"errors"
 	"fmt"
+	"sync"
+	"sync/atomic"
 	"testing"
 
 	"github.com/goosz/modz"
@@ -12,6 +15,42 @@ import (
 var (
 	fooKey = modz.NewData[int]("foo")
 	barKey = modz.NewData[int]("bar")
+
+	errPortRange = errors.New("port out of range")
+	errPortZero  = errors.New("port is zero")
+	portKey      = modz.NewData("port",
+		modz.WithValidator(func(port int) error {
+			if port == 0 {
+				return errPortZero
+			}
+			return nil
+		}),
+		modz.WithValidator(func(port int) error {
+			if port < 0 || port > 65535 {
+				return errPortRange
+			}
+			return nil
+		}),
+	)
+
+	timeoutKey   = modz.NewData("timeout", modz.WithDefault(30))
+	defaultCalls atomic.Int32
+	errNoRegion  = errors.New("no region")
+	regionKey    = modz.NewData("region", modz.WithDefaultFunc(func() (string, error) {
+		if defaultCalls.Add(1) > 1 {
+			return "", errNoRegion
+		}
+		return "eu-west-1", nil
+	}))
+	zoneKey = modz.NewData[string]("zone")
+
+	errNotString = errors.New("not a string")
+	anythingKey  = modz.NewData("anything", modz.WithValidator(func(v any) error {
+		if _, ok := v.(string); !ok {
+			return errNotString
+		}
+		return nil
+	}))
 )
 
 func TestData_PutAndGet(t *testing.T) {
@@ -113,3 +152,403 @@ func TestNewData_PanicWhenCalledFromClosure(t *testing.T) {
 		modz.NewData[string]("test-key")
 	}()
 }
+
+func TestData_Lookup(t *testing.T) {
+	mock := modz.NewMockDataReadWriter()
+	require.NoError(t, fooKey.Put(mock, 42))
+
+	val, found, err := fooKey.Lookup(mock)
+	require.NoError(t, err)
+	require.True(t, found)
+	require.Equal(t, 42, val)
+
+	// Errors other than an absent value are reported.
+	_, found, err = barKey.Lookup(mock)
+	require.Error(t, err)
+	require.False(t, found)
+
+	_, _, err = fooKey.Lookup(nil)
+	require.Error(t, err)
+}
+
+func TestData_Provide(t *testing.T) {
+	calls := 0
+	producer := &modz.MockModule{
+		NameValue:     "producer",
+		ProducesValue: modz.Keys(fooKey),
+		ConfigureFunc: func(b modz.Binder) error {
+			return fooKey.Provide(b, func() (int, error) {
+				calls++
+				return 42, nil
+			})
+		},
+	}
+	var got int
+	consumer := &modz.MockModule{
+		NameValue:     "consumer",
+		ConsumesValue: modz.Keys(fooKey),
+		ConfigureFunc: func(b modz.Binder) error {
+			require.Equal(t, 0, calls, "the value must not be computed before the first Get")
+			var err error
+			got, err = fooKey.Get(b)
+			return err
+		},
+	}
+	asm, err := modz.NewAssembly(producer, consumer)
+	require.NoError(t, err)
+	require.NoError(t, asm.Build())
+	require.Equal(t, 42, got)
+
+	val, err := fooKey.Get(asm)
+	require.NoError(t, err)
+	require.Equal(t, 42, val)
+	require.Equal(t, 1, calls)
+}
+
+func TestData_Provide_Concurrent(t *testing.T) {
+	var calls atomic.Int32
+	producer := &modz.MockModule{
+		NameValue:     "producer",
+		ProducesValue: modz.Keys(fooKey),
+		ConfigureFunc: func(b modz.Binder) error {
+			return fooKey.Provide(b, func() (int, error) {
+				calls.Add(1)
+				return 7, nil
+			})
+		},
+	}
+	asm, err := modz.NewAssembly(producer)
+	require.NoError(t, err)
+	require.NoError(t, asm.Build())
+
+	var wg sync.WaitGroup
+	for range 10 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			val, err := fooKey.Get(asm)
+			require.NoError(t, err)
+			require.Equal(t, 7, val)
+		}()
+	}
+	wg.Wait()
+	require.Equal(t, int32(1), calls.Load())
+}
+
+func TestData_Provide_Error(t *testing.T) {
+	producer := &modz.MockModule{
+		NameValue:     "producer",
+		ProducesValue: modz.Keys(fooKey),
+		ConfigureFunc: func(b modz.Binder) error {
+			return fooKey.Provide(b, func() (int, error) {
+				return 0, errors.New("connection refused")
+			})
+		},
+	}
+	consumer := &modz.MockModule{
+		NameValue:     "consumer",
+		ConsumesValue: modz.Keys(fooKey),
+		ConfigureFunc: func(b modz.Binder) error {
+			_, err := fooKey.Get(b)
+			return err
+		},
+	}
+	asm, err := modz.NewAssembly(producer, consumer)
+	require.NoError(t, err)
+
+	err = asm.Build()
+	require.ErrorContains(t, err, "connection refused")
+	var configErr *modz.ConfigurationError
+	require.ErrorAs(t, err, &configErr)
+	require.Equal(t, "github.com/goosz/modz:consumer", configErr.ModuleID)
+	var provideErr *modz.ConfigurationError
+	require.ErrorAs(t, configErr.Err, &provideErr)
+	require.Equal(t, "github.com/goosz/modz:producer", provideErr.ModuleID)
+	require.Equal(t, "Provide", provideErr.Operation)
+}
+
+func TestData_Provide_NilArguments(t *testing.T) {
+	mock := modz.NewMockDataReadWriter()
+	require.ErrorIs(t, fooKey.Provide(nil, func() (int, error) { return 0, nil }), modz.ErrInvalidArgument)
+	require.ErrorIs(t, fooKey.Provide(mock, nil), modz.ErrInvalidArgument)
+}
+
+func TestData_WithValidator(t *testing.T) {
+	store := modz.NewDataStore()
+
+	err := portKey.Put(store, 0)
+	require.ErrorIs(t, err, modz.ErrInvalidValue)
+	require.ErrorIs(t, err, errPortZero)
+	require.ErrorContains(t, err, "port")
+	err = portKey.Put(store, 70000)
+	require.ErrorIs(t, err, errPortRange)
+	require.False(t, store.Has(portKey))
+
+	require.NoError(t, portKey.Put(store, 8080))
+	val, err := portKey.Get(store)
+	require.NoError(t, err)
+	require.Equal(t, 8080, val)
+}
+
+func TestData_WithValidator_Put(t *testing.T) {
+	producer := &modz.MockModule{
+		NameValue:     "producer",
+		ProducesValue: modz.Keys(portKey),
+		ConfigureFunc: func(b modz.Binder) error {
+			return portKey.Put(b, -1)
+		},
+	}
+	asm, err := modz.NewAssembly(producer)
+	require.NoError(t, err)
+
+	err = asm.Build()
+	require.ErrorIs(t, err, modz.ErrInvalidValue)
+	require.ErrorIs(t, err, errPortRange)
+	var configErr *modz.ConfigurationError
+	require.ErrorAs(t, err, &configErr)
+	require.Equal(t, "github.com/goosz/modz:producer", configErr.ModuleID)
+	var dataErr *modz.Error
+	require.ErrorAs(t, err, &dataErr)
+	require.Equal(t, portKey, dataErr.Key)
+}
+
+func TestData_WithValidator_Provide(t *testing.T) {
+	producer := &modz.MockModule{
+		NameValue:     "producer",
+		ProducesValue: modz.Keys(portKey),
+		ConfigureFunc: func(b modz.Binder) error {
+			return portKey.Provide(b, func() (int, error) { return 0, nil })
+		},
+	}
+	asm, err := modz.NewAssembly(producer)
+	require.NoError(t, err)
+	require.NoError(t, asm.Build())
+
+	_, err = portKey.Get(asm)
+	require.ErrorIs(t, err, modz.ErrInvalidValue)
+	var provideErr *modz.ConfigurationError
+	require.ErrorAs(t, err, &provideErr)
+	require.Equal(t, "github.com/goosz/modz:producer", provideErr.ModuleID)
+	require.Equal(t, "Provide", provideErr.Operation)
+}
+
+func TestData_WithValidator_ProvideAny(t *testing.T) {
+	// The validator of a Data[any] key runs on the computed value, not on the lazy wrapper.
+	for name, value := range map[string]any{"valid": "ok", "invalid": 42} {
+		t.Run(name, func(t *testing.T) {
+			provide := func() (any, error) { return value, nil }
+			producer := &modz.MockModule{
+				NameValue:     "producer",
+				ProducesValue: modz.Keys(anythingKey),
+				ConfigureFunc: func(b modz.Binder) error {
+					return anythingKey.Provide(b, provide)
+				},
+			}
+			asm, err := modz.NewAssembly(producer)
+			require.NoError(t, err)
+			require.NoError(t, asm.Build())
+
+			store := modz.NewDataStore()
+			require.NoError(t, anythingKey.Provide(store, provide))
+
+			for _, r := range []modz.DataReader{asm, store} {
+				val, err := anythingKey.Get(r)
+				if name == "valid" {
+					require.NoError(t, err)
+					require.Equal(t, value, val)
+				} else {
+					require.ErrorIs(t, err, modz.ErrInvalidValue)
+					require.ErrorIs(t, err, errNotString)
+				}
+			}
+		})
+	}
+}
+
+func TestData_WithValidator_WithData(t *testing.T) {
+	_, err := modz.NewAssemblyWithOptions([]modz.AssemblyOption{modz.WithData(portKey, 0)})
+	require.ErrorIs(t, err, modz.ErrInvalidValue)
+	require.ErrorIs(t, err, errPortZero)
+
+	asm, err := modz.NewAssemblyWithOptions([]modz.AssemblyOption{modz.WithData(portKey, 443)})
+	require.NoError(t, err)
+	require.NoError(t, asm.Build())
+	val, err := portKey.Get(asm)
+	require.NoError(t, err)
+	require.Equal(t, 443, val)
+}
+
+func TestData_WithDefault(t *testing.T) {
+	var got int
+	var present bool
+	consumer := &modz.MockModule{
+		NameValue:     "consumer",
+		ConsumesValue: modz.Keys(timeoutKey),
+		ConfigureFunc: func(b modz.Binder) error {
+			var err error
+			got, err = timeoutKey.Get(b)
+			return err
+		},
+	}
+	optional := &modz.MockModule{
+		NameValue:             "optional",
+		OptionalConsumesValue: modz.Keys(timeoutKey),
+		ConfigureFunc: func(b modz.Binder) error {
+			var err error
+			_, present, err = timeoutKey.Lookup(b)
+			return err
+		},
+	}
+	asm, err := modz.NewAssembly(consumer, optional)
+	require.NoError(t, err)
+	require.NoError(t, asm.Build())
+	require.Equal(t, 30, got)
+	require.True(t, present)
+
+	require.Equal(t, []modz.DataKey{timeoutKey}, asm.BuildReport().Defaults)
+	entries := asm.DataEntries()
+	require.Len(t, entries, 1)
+	require.True(t, entries[0].Defaulted)
+	require.Empty(t, entries[0].Producers)
+}
+
+func TestData_WithDefault_Produced(t *testing.T) {
+	producer := &modz.MockModule{
+		NameValue:     "producer",
+		ProducesValue: modz.Keys(timeoutKey),
+		ConfigureFunc: func(b modz.Binder) error {
+			return timeoutKey.Put(b, 5)
+		},
+	}
+	consumer := &modz.MockModule{
+		NameValue:     "consumer",
+		ConsumesValue: modz.Keys(timeoutKey),
+	}
+	asm, err := modz.NewAssembly(consumer, producer)
+	require.NoError(t, err)
+	require.NoError(t, asm.Build())
+
+	val, err := timeoutKey.Get(asm)
+	require.NoError(t, err)
+	require.Equal(t, 5, val)
+	require.Empty(t, asm.BuildReport().Defaults)
+	require.False(t, asm.DataEntries()[0].Defaulted)
+}
+
+func TestData_WithDefault_ProducerFailed(t *testing.T) {
+	producer := &modz.MockModule{
+		NameValue:     "producer",
+		ProducesValue: modz.Keys(timeoutKey),
+		ConfigureFunc: func(b modz.Binder) error {
+			return errors.New("boom")
+		},
+	}
+	consumer := &modz.MockModule{
+		NameValue:     "consumer",
+		ConsumesValue: modz.Keys(timeoutKey),
+	}
+	asm, err := modz.NewAssemblyWithOptions([]modz.AssemblyOption{modz.WithAggregateErrors()}, consumer, producer)
+	require.NoError(t, err)
+
+	err = asm.Build()
+	var asmErr *modz.AssemblyError
+	require.ErrorAs(t, err, &asmErr)
+	require.Equal(t, []string{"github.com/goosz/modz:consumer"}, asmErr.Skipped)
+	require.Empty(t, asm.BuildReport().Defaults)
+}
+
+func TestData_WithDefault_LateProducer(t *testing.T) {
+	producer := &modz.MockModule{
+		NameValue:     "producer",
+		ProducesValue: modz.Keys(timeoutKey),
+	}
+	consumer := &modz.MockModule{
+		NameValue:     "consumer",
+		ConsumesValue: modz.Keys(timeoutKey),
+		ConfigureFunc: func(b modz.Binder) error {
+			return b.Install(producer)
+		},
+	}
+	asm, err := modz.NewAssembly(consumer)
+	require.NoError(t, err)
+
+	err = asm.Build()
+	require.ErrorIs(t, err, modz.ErrAlreadySet)
+	require.ErrorContains(t, err, "already set to its default value")
+}
+
+func TestData_WithDefault_ProducerInstalledAfterSettling(t *testing.T) {
+	// The installer only becomes ready once settle resolves a key it consumes: ListKey, which
+	// has no contributor, zoneKey, read from the parent assembly, or fooKey, optionally
+	// consumed and never produced. The producer it installs takes precedence over the default.
+	parent, err := modz.NewAssembly(&modz.MockModule{
+		NameValue:     "zone",
+		ProducesValue: modz.Keys(zoneKey),
+		ConfigureFunc: func(b modz.Binder) error { return zoneKey.Put(b, "a") },
+	})
+	require.NoError(t, err)
+	require.NoError(t, parent.Build())
+
+	for name, declare := range map[string]func(m *modz.MockModule){
+		"collection": func(m *modz.MockModule) { m.ConsumesValue = modz.Keys(modz.ListKey) },
+		"parent":     func(m *modz.MockModule) { m.ConsumesValue = modz.Keys(zoneKey) },
+		"optional":   func(m *modz.MockModule) { m.OptionalConsumesValue = modz.Keys(fooKey) },
+	} {
+		t.Run(name, func(t *testing.T) {
+			producer := &modz.MockModule{
+				NameValue:     "producer",
+				ProducesValue: modz.Keys(timeoutKey),
+				ConfigureFunc: func(b modz.Binder) error {
+					return timeoutKey.Put(b, 5)
+				},
+			}
+			installer := &modz.MockModule{
+				NameValue: "installer",
+				ConfigureFunc: func(b modz.Binder) error {
+					return b.Install(producer)
+				},
+			}
+			declare(installer)
+			var got int
+			consumer := &modz.MockModule{
+				NameValue:     "consumer",
+				ConsumesValue: modz.Keys(timeoutKey),
+				ConfigureFunc: func(b modz.Binder) (err error) {
+					got, err = timeoutKey.Get(b)
+					return err
+				},
+			}
+			asm, err := modz.NewAssemblyWithOptions([]modz.AssemblyOption{modz.WithParent(parent)}, consumer, installer)
+			require.NoError(t, err)
+			require.NoError(t, asm.Build())
+			require.Equal(t, 5, got)
+			require.Empty(t, asm.BuildReport().Defaults)
+		})
+	}
+}
+
+func TestData_WithDefaultFunc(t *testing.T) {
+	defaultCalls.Store(0)
+	consumer := &modz.MockModule{
+		NameValue:     "consumer",
+		ConsumesValue: modz.Keys(regionKey),
+	}
+	asm, err := modz.NewAssembly(consumer)
+	require.NoError(t, err)
+	require.NoError(t, asm.Build())
+	require.Equal(t, int32(0), defaultCalls.Load(), "the default must not be computed before the first Get")
+
+	for range 2 {
+		val, err := regionKey.Get(asm)
+		require.NoError(t, err)
+		require.Equal(t, "eu-west-1", val)
+	}
+	require.Equal(t, int32(1), defaultCalls.Load())
+
+	// Each assembly computes its own default.
+	asm, err = modz.NewAssembly(consumer)
+	require.NoError(t, err)
+	require.NoError(t, asm.Build())
+	_, err = regionKey.Get(asm)
+	require.ErrorIs(t, err, errNoRegion)
+}
@@ -0,0 +1,219 @@
+package modz
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// Decorator is implemented by modules that wrap the value of [Data] keys produced by other
+// modules, for example to add metrics, retries or caching to a client, without the producer
+// knowing about it.
+//
+// A decorator declares the keys it decorates in Decorates(). During Configure(), it reads the
+// current value of each of them with Get() and stores the wrapped value with Put(); a
+// decorator that does not Put a key leaves its value unchanged. The modules consuming a
+// decorated key are configured only after every decorator of the key has been configured,
+// and read the value stored by the last one.
+//
+// The decorators of a key are configured one at a time, in the order of their module
+// signatures, each reading the value stored by the previous one. A decorator is configured
+// once the value it decorates is available and the keys it declares in Consumes() and
+// OptionalConsumes() are satisfied. If a decorator fails, the modules consuming the key are
+// not configured.
+//
+// A decorator must be installed before the value of the key is available to its consumers,
+// typically by passing it to [NewAssembly] along with the modules producing the key;
+// installing it afterwards fails with [ErrAlreadySet]. A decorated key without a producer is
+// decorated from the value of a parent assembly (see [WithParent]) or its default value (see
+// [WithDefault]), if any. [SetData] and [MapData] keys cannot be decorated. A key must not be
+// declared in both Decorates() and Produces(), Consumes() or OptionalConsumes(). Like
+// Consumes(), Decorates() must be deterministic.
+type Decorator interface {
+	Module
+
+	// Decorates returns the [DataKey]s whose values this module wraps.
+	Decorates() DataKeys
+}
+
+// decoration tracks the value of a decorated key as it is passed from decorator to decorator.
+type decoration struct {
+	// available is true once the undecorated value has been stored.
+	available bool
+	value     any
+	// current is the decorator the value was last passed to, if any.
+	current *binder
+}
+
+// addDecorator registers the module bound to b as a decorator of k, keeping the decorators
+// of k sorted by module signature.
+//
+// The caller must hold a.mu.
+func (a *assembly) addDecorator(k DataKey, b *binder) {
+	decorators := append(a.decorators[k], b)
+	slices.SortFunc(decorators, func(x, y *binder) int {
+		return strings.Compare(x.moduleSignature.String(), y.moduleSignature.String())
+	})
+	a.decorators[k] = decorators
+	if _, ok := a.decorations[k]; !ok {
+		a.decorations[k] = &decoration{}
+	}
+}
+
+// decoratedValue returns the current value of the decorated key k, for its decorator bound
+// to b. This is used internally by the binder.
+func (a *assembly) decoratedValue(b *binder, k DataKey) (any, error) {
+	a.mu.RLock()
+	var val any
+	d := a.decorations[k]
+	turn := d != nil && d.current == b && d.available
+	if turn {
+		val = d.value
+	}
+	a.mu.RUnlock()
+	if !turn {
+		return nil, newDataOperationError(ErrNotProduced, k, fmt.Sprintf("not yet passed to decorator '%s'", b.moduleSignature))
+	}
+	if lv, ok := val.(*lazyValue); ok {
+		return lv.get()
+	}
+	return val, nil
+}
+
+// decorateDataValue replaces the current value of the decorated key k with the value stored
+// by its decorator bound to b. This is used internally by the binder.
+func (a *assembly) decorateDataValue(b *binder, k DataKey, value any) error {
+	if vk, ok := k.(validatedKey); ok {
+		if err := vk.validate(value); err != nil {
+			return err
+		}
+	}
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	d := a.decorations[k]
+	if d == nil || d.current != b || !d.available {
+		return newDataOperationError(ErrNotProduced, k, fmt.Sprintf("not yet passed to decorator '%s'", b.moduleSignature))
+	}
+	if _, ok := b.produced[k]; ok {
+		return newDataOperationError(ErrAlreadySet, k, "already decorated")
+	}
+	d.value = value
+	return nil
+}
+
+// advanceDecorations passes the value of a decorated key to its next decorator once the
+// previous one has been configured, or, after the last decorator, stores the decorated value
+// for the modules consuming the key. The undecorated value of a key without a producer is
+// only available once resolveUndecorated has set it. It returns as soon as a module becomes
+// ready.
+//
+// Returns true if any module became ready.
+//
+// The caller must hold a.mu, and no module may be ready or being configured.
+func (a *assembly) advanceDecorations() bool {
+	for _, k := range sortedKeys(a.decorations) {
+		d := a.decorations[k]
+		if _, done := a.data[k]; done {
+			continue
+		}
+		if !d.available {
+			continue
+		}
+		if d.current != nil && (!d.current.configured.Load() || d.current.failed.Load()) {
+			// The previous decorator has not been configured yet, or failed.
+			continue
+		}
+		i := slices.IndexFunc(a.decorators[k], func(b *binder) bool { return !b.configured.Load() })
+		if i < 0 {
+			if a.storeDataValue(k, d.value) {
+				return true
+			}
+			continue
+		}
+		d.current = a.decorators[k][i]
+		if d.current.resolveDependency(k) {
+			a.schedule(d.current)
+			return true
+		}
+	}
+	return false
+}
+
+// resolveUndecorated sets the undecorated value of the decorated keys without a producer from
+// the parent assembly or, if defaults is true, from the key's default value, and passes it to
+// their first decorator. Like the values of keys without decorators, it is called from
+// resolveInherited and applyDefaults, once no module can be made ready otherwise, since that
+// module may still install their producer. It returns as soon as a module becomes ready.
+//
+// Returns true if any module became ready.
+//
+// The caller must hold a.mu.
+func (a *assembly) resolveUndecorated(defaults bool) bool {
+	for _, k := range sortedKeys(a.decorations) {
+		d := a.decorations[k]
+		if d.available {
+			continue
+		}
+		if _, ok := a.producers[k]; ok {
+			// The producer has not stored the value yet.
+			continue
+		}
+		if value, ok := a.parentValue(k); ok {
+			a.inherited[k] = struct{}{}
+			d.value, d.available = value, true
+		} else if dk, ok := k.(defaultedKey); ok && defaults {
+			value, ok := dk.newDefault()
+			if !ok {
+				continue
+			}
+			a.defaulted[k] = struct{}{}
+			d.value, d.available = value, true
+		} else {
+			continue
+		}
+		if a.advanceDecorations() {
+			return true
+		}
+	}
+	return false
+}
+
+// undecoratable reports whether k is a decorated key whose undecorated value will never be
+// available: it has no producer, and neither a parent assembly nor a default provide it.
+//
+// The caller must hold a.mu.
+func (a *assembly) undecoratable(k DataKey) bool {
+	d, ok := a.decorations[k]
+	if !ok || d.available {
+		return false
+	}
+	if _, ok := a.producers[k]; ok {
+		return false
+	}
+	if a.parentHas(k) {
+		return false
+	}
+	return !hasDefault(k)
+}
+
+// dependenciesOf returns the modules that must be configured before the module bound to b
+// can read the value of k: for a decorator of k, the producer of k and the decorators
+// preceding b; otherwise, the producers of k (see producersOf).
+//
+// The caller must hold a.mu.
+func (a *assembly) dependenciesOf(b *binder, k DataKey) []*binder {
+	if _, decorates := b.decorates[k]; !decorates {
+		return a.producersOf(k)
+	}
+	var deps []*binder
+	if p, ok := a.producers[k]; ok {
+		deps = append(deps, p)
+	}
+	for _, d := range a.decorators[k] {
+		if d == b {
+			break
+		}
+		deps = append(deps, d)
+	}
+	return deps
+}
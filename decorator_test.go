@@ -0,0 +1,363 @@
+package modz
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// newAppendingDecorator returns a decorator of ProducedKey appending suffix to its value.
+func newAppendingDecorator(name, suffix string) *MockDecoratorModule {
+	return &MockDecoratorModule{
+		MockModule: MockModule{
+			NameValue: name,
+			ConfigureFunc: func(b Binder) error {
+				val, err := ProducedKey.Get(b)
+				if err != nil {
+					return err
+				}
+				return ProducedKey.Put(b, val+suffix)
+			},
+		},
+		DecoratesValue: Keys(ProducedKey),
+	}
+}
+
+// newStringProducer returns a module producing value under ProducedKey.
+func newStringProducer(value string) *MockModule {
+	return &MockModule{
+		NameValue:     "producer",
+		ProducesValue: Keys(ProducedKey),
+		ConfigureFunc: func(b Binder) error {
+			return ProducedKey.Put(b, value)
+		},
+	}
+}
+
+// newStringConsumer returns a module recording the value of ProducedKey in got.
+func newStringConsumer(got *string) *MockModule {
+	return &MockModule{
+		NameValue:     "consumer",
+		ConsumesValue: Keys(ProducedKey),
+		ConfigureFunc: func(b Binder) error {
+			var err error
+			*got, err = ProducedKey.Get(b)
+			return err
+		},
+	}
+}
+
+func TestDecorator_Chain(t *testing.T) {
+	for _, parallelism := range []int{1, 4} {
+		var got string
+		asm, err := NewAssemblyWithOptions([]AssemblyOption{WithParallelism(parallelism)},
+			newStringConsumer(&got),
+			newAppendingDecorator("decorator-b", "+b"),
+			newStringProducer("x"),
+			newAppendingDecorator("decorator-a", "+a"),
+		)
+		require.NoError(t, err)
+		require.NoError(t, asm.Build())
+
+		require.Equal(t, "x+a+b", got)
+		val, err := ProducedKey.Get(asm)
+		require.NoError(t, err)
+		require.Equal(t, "x+a+b", val)
+	}
+}
+
+func TestDecorator_Order(t *testing.T) {
+	var order []string
+	asm, err := NewAssembly(
+		newAppendingDecorator("decorator", "+d"),
+		newStringProducer("x"),
+		&MockModule{
+			NameValue:     "consumer",
+			ConsumesValue: Keys(ProducedKey),
+			ConfigureFunc: func(b Binder) error {
+				order = append(order, "consumer")
+				return nil
+			},
+		},
+	)
+	require.NoError(t, err)
+	require.NoError(t, asm.Build())
+	require.Equal(t, []string{"consumer"}, order)
+
+	var ids []string
+	for _, m := range asm.BuildReport().Modules {
+		ids = append(ids, m.ModuleID)
+	}
+	require.Equal(t, []string{
+		"github.com/goosz/modz:producer",
+		"github.com/goosz/modz:decorator",
+		"github.com/goosz/modz:consumer",
+	}, ids)
+}
+
+func TestDecorator_PassThrough(t *testing.T) {
+	var got string
+	decorator := &MockDecoratorModule{
+		MockModule:     MockModule{NameValue: "decorator"},
+		DecoratesValue: Keys(ProducedKey),
+	}
+	asm, err := NewAssembly(newStringConsumer(&got), decorator, newStringProducer("x"))
+	require.NoError(t, err)
+	require.NoError(t, asm.Build())
+	require.Equal(t, "x", got)
+}
+
+func TestDecorator_ConsumesOtherKeys(t *testing.T) {
+	var got string
+	decorator := newAppendingDecorator("decorator", "")
+	decorator.ConsumesValue = Keys(FooKey)
+	decorator.ConfigureFunc = func(b Binder) error {
+		val, err := ProducedKey.Get(b)
+		if err != nil {
+			return err
+		}
+		foo, err := FooKey.Get(b)
+		if err != nil {
+			return err
+		}
+		return ProducedKey.Put(b, val+string(rune('0'+foo)))
+	}
+	fooProducer := &MockModule{
+		NameValue:     "foo-producer",
+		ProducesValue: Keys(FooKey),
+		ConfigureFunc: func(b Binder) error {
+			return FooKey.Put(b, 7)
+		},
+	}
+	asm, err := NewAssembly(newStringConsumer(&got), decorator, newStringProducer("x"), fooProducer)
+	require.NoError(t, err)
+	require.NoError(t, asm.Build())
+	require.Equal(t, "x7", got)
+}
+
+func TestDecorator_Failure(t *testing.T) {
+	var got string
+	errBoom := errors.New("boom")
+	decorator := newAppendingDecorator("decorator", "")
+	decorator.ConfigureFunc = func(Binder) error { return errBoom }
+	asm, err := NewAssemblyWithOptions([]AssemblyOption{WithAggregateErrors()},
+		newStringConsumer(&got), decorator, newStringProducer("x"))
+	require.NoError(t, err)
+
+	err = asm.Build()
+	require.ErrorIs(t, err, errBoom)
+	var asmErr *AssemblyError
+	require.ErrorAs(t, err, &asmErr)
+	require.Equal(t, []string{"github.com/goosz/modz:consumer"}, asmErr.Skipped)
+	require.Empty(t, got)
+}
+
+func TestDecorator_Lookup(t *testing.T) {
+	var present bool
+	decorator := newAppendingDecorator("decorator", "")
+	decorator.ConfigureFunc = func(b Binder) error {
+		var err error
+		_, present, err = ProducedKey.Lookup(b)
+		return err
+	}
+	asm, err := NewAssembly(decorator, newStringProducer("x"))
+	require.NoError(t, err)
+	require.NoError(t, asm.Build())
+	require.True(t, present)
+}
+
+func TestDecorator_PutTwice(t *testing.T) {
+	decorator := newAppendingDecorator("decorator", "")
+	decorator.ConfigureFunc = func(b Binder) error {
+		if err := ProducedKey.Put(b, "a"); err != nil {
+			return err
+		}
+		return ProducedKey.Put(b, "b")
+	}
+	asm, err := NewAssembly(decorator, newStringProducer("x"))
+	require.NoError(t, err)
+	require.ErrorIs(t, asm.Build(), ErrAlreadySet)
+}
+
+func TestDecorator_InstalledTooLate(t *testing.T) {
+	var got string
+	consumer := newStringConsumer(&got)
+	consumer.ConfigureFunc = func(b Binder) error {
+		return b.Install(newAppendingDecorator("decorator", "+d"))
+	}
+	asm, err := NewAssembly(consumer, newStringProducer("x"))
+	require.NoError(t, err)
+
+	err = asm.Build()
+	require.ErrorIs(t, err, ErrAlreadySet)
+	require.ErrorContains(t, err, "cannot decorate a key whose value is already available")
+}
+
+func TestDecorator_MissingProducer(t *testing.T) {
+	var got string
+	asm, err := NewAssembly(newStringConsumer(&got), newAppendingDecorator("decorator", "+d"))
+	require.NoError(t, err)
+
+	err = asm.Build()
+	var missingErr *MissingProducerError
+	require.ErrorAs(t, err, &missingErr)
+	require.Equal(t, ProducedKey, missingErr.Key)
+	require.Equal(t, []string{"github.com/goosz/modz:consumer", "github.com/goosz/modz:decorator"}, missingErr.ModuleIDs)
+
+	// A decorator without consumers is reported too.
+	asm, err = NewAssembly(newAppendingDecorator("decorator", "+d"))
+	require.NoError(t, err)
+	err = asm.Build()
+	require.ErrorAs(t, err, &missingErr)
+	require.Equal(t, []string{"github.com/goosz/modz:decorator"}, missingErr.ModuleIDs)
+}
+
+func TestDecorator_OptionalConsumerOfUndecoratableKey(t *testing.T) {
+	var present bool
+	consumer := &MockModule{
+		NameValue:             "consumer",
+		OptionalConsumesValue: Keys(ProducedKey),
+		ConfigureFunc: func(b Binder) error {
+			var err error
+			_, present, err = ProducedKey.Lookup(b)
+			return err
+		},
+	}
+	asm, err := NewAssembly(consumer, newAppendingDecorator("decorator", "+d"))
+	require.NoError(t, err)
+
+	err = asm.Build()
+	require.ErrorIs(t, err, ErrMissingProducer)
+	require.False(t, present)
+	require.Equal(t, "github.com/goosz/modz:consumer", asm.BuildReport().Modules[0].ModuleID)
+}
+
+func TestDecorator_FromParent(t *testing.T) {
+	parent, err := NewAssembly(newStringProducer("x"))
+	require.NoError(t, err)
+	require.NoError(t, parent.Build())
+
+	var got string
+	child, err := NewAssemblyWithOptions([]AssemblyOption{WithParent(parent)},
+		newStringConsumer(&got), newAppendingDecorator("decorator", "+d"))
+	require.NoError(t, err)
+	require.NoError(t, child.Build())
+	require.Equal(t, "x+d", got)
+
+	val, err := ProducedKey.Get(parent)
+	require.NoError(t, err)
+	require.Equal(t, "x", val)
+}
+
+func TestDecorator_ProducerInstalledAfterSettling(t *testing.T) {
+	// The installer only becomes ready once settle resolves FooKey as absent. The producer it
+	// installs takes precedence over the default of the decorated key.
+	decorator := &MockDecoratorModule{
+		MockModule: MockModule{
+			NameValue: "decorator",
+			ConfigureFunc: func(b Binder) error {
+				val, err := RetriesKey.Get(b)
+				if err != nil {
+					return err
+				}
+				return RetriesKey.Put(b, val*2)
+			},
+		},
+		DecoratesValue: Keys(RetriesKey),
+	}
+	producer := &MockModule{
+		NameValue:     "producer",
+		ProducesValue: Keys(RetriesKey),
+		ConfigureFunc: func(b Binder) error { return RetriesKey.Put(b, 5) },
+	}
+	installer := &MockModule{
+		NameValue:             "installer",
+		OptionalConsumesValue: Keys(FooKey),
+		ConfigureFunc:         func(b Binder) error { return b.Install(producer) },
+	}
+	var got int
+	consumer := &MockModule{
+		NameValue:     "consumer",
+		ConsumesValue: Keys(RetriesKey),
+		ConfigureFunc: func(b Binder) (err error) {
+			got, err = RetriesKey.Get(b)
+			return err
+		},
+	}
+	asm, err := NewAssembly(consumer, decorator, installer)
+	require.NoError(t, err)
+	require.NoError(t, asm.Build())
+	require.Equal(t, 10, got)
+	require.Empty(t, asm.BuildReport().Defaults)
+
+	// Without a producer, the default value is decorated.
+	asm, err = NewAssembly(consumer, decorator)
+	require.NoError(t, err)
+	require.NoError(t, asm.Build())
+	require.Equal(t, 6, got)
+	require.Equal(t, []DataKey{RetriesKey}, asm.BuildReport().Defaults)
+}
+
+func TestDecorator_Cycle(t *testing.T) {
+	// The decorator consumes FooKey, produced by a consumer of the decorated key.
+	decorator := newAppendingDecorator("decorator", "")
+	decorator.ConsumesValue = Keys(FooKey)
+	consumer := &MockModule{
+		NameValue:     "consumer",
+		ProducesValue: Keys(FooKey),
+		ConsumesValue: Keys(ProducedKey),
+	}
+	asm, err := NewAssembly(decorator, consumer, newStringProducer("x"))
+	require.NoError(t, err)
+
+	err = asm.Build()
+	var cycleErr *CycleError
+	require.ErrorAs(t, err, &cycleErr)
+	require.Len(t, cycleErr.Cycle, 2)
+}
+
+func TestDecorator_InvalidDeclarations(t *testing.T) {
+	decorator := newAppendingDecorator("decorator", "")
+	decorator.ConsumesValue = Keys(ProducedKey)
+	_, err := NewAssembly(decorator)
+	require.ErrorIs(t, err, ErrDuplicateDeclaration)
+	require.EqualError(t, err, fmt.Sprintf("module 'github.com/goosz/modz:decorator' declares '%s' in both Consumes and Decorates", ProducedKey))
+
+	producer := newAppendingDecorator("producer", "")
+	producer.ProducesValue = Keys(ProducedKey)
+	_, err = NewAssembly(producer)
+	require.ErrorIs(t, err, ErrDuplicateDeclaration)
+	require.ErrorContains(t, err, "in both Produces and Decorates")
+
+	collection := &MockDecoratorModule{
+		MockModule:     MockModule{NameValue: "collection"},
+		DecoratesValue: Keys(ListKey),
+	}
+	_, err = NewAssembly(collection)
+	require.ErrorIs(t, err, ErrInvalidArgument)
+}
+
+func TestDecorator_UndeclaredPut(t *testing.T) {
+	decorator := newAppendingDecorator("decorator", "")
+	decorator.ConfigureFunc = func(b Binder) error {
+		return FooKey.Put(b, 1)
+	}
+	asm, err := NewAssembly(decorator, newStringProducer("x"))
+	require.NoError(t, err)
+	require.ErrorIs(t, asm.Build(), ErrUndeclaredKey)
+}
+
+func TestDecorator_Graph(t *testing.T) {
+	asm, err := NewAssembly(newAppendingDecorator("decorator", "+d"), newStringProducer("x"))
+	require.NoError(t, err)
+
+	g := asm.Graph()
+	require.Contains(t, g.Edges, GraphEdge{
+		Module: "github.com/goosz/modz:decorator",
+		Key:    "github.com/goosz/modz:produced",
+		Kind:   EdgeDecorates,
+		Label:  ProducedKey.(*dataKey[string]).String(),
+	})
+}
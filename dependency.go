@@ -0,0 +1,176 @@
+package modz
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+)
+
+// diagnoseWaiters explains why modules are still waiting for data keys once no further module
+// can be configured. Every waited-for key without a producer is reported as a
+// [MissingProducerError], and every circular dependency between waiting modules is reported
+// as a [CycleError], in a deterministic order. Waiting modules blocked, directly or through
+// other waiting modules, by a producer whose configuration failed are returned as skipped.
+//
+// The caller must hold a.mu.
+func (a *assembly) diagnoseWaiters() ([]error, []string) {
+	var errs []error
+	waiting := a.waitingModules()
+	for _, k := range sortedKeys(waiting) {
+		if _, ok := k.(collectionKey); ok || (len(a.producersOf(k)) > 0 && !a.undecoratable(k)) {
+			continue
+		}
+		errs = append(errs, &MissingProducerError{Key: k, ModuleIDs: moduleIDs(waiting[k])})
+	}
+	errs = append(errs, a.findCycles()...)
+	skipped := a.skippedModules()
+	if len(errs) == 0 && len(skipped) == 0 {
+		// Every waited-for key has a producer that is not itself waiting; this should not happen.
+		errs = append(errs, fmt.Errorf("some modules are still waiting for data keys: %v", sortedKeys(waiting)))
+	}
+	return errs, skipped
+}
+
+// waitingModules returns the modules waiting for each data key that will not be produced
+// without further progress: the modules waiting for the key's value, and the decorators of a
+// decorated key whose undecorated value will never be available.
+//
+// The caller must hold a.mu.
+func (a *assembly) waitingModules() map[DataKey][]*binder {
+	waiting := maps.Clone(a.waiters)
+	for k := range a.decorations {
+		if a.undecoratable(k) {
+			waiting[k] = slices.Concat(waiting[k], a.decorators[k])
+		}
+	}
+	return waiting
+}
+
+// skippedModules returns the sorted signatures of the waiting modules that can never be
+// configured because a producer they depend on, directly or transitively, failed.
+//
+// The caller must hold a.mu.
+func (a *assembly) skippedModules() []string {
+	pending := a.sortedBinders()
+	blocked := make(map[*binder]bool)
+	for changed := true; changed; {
+		changed = false
+		for _, b := range pending {
+			if b.isReady() || blocked[b] {
+				continue
+			}
+			for k := range b.waiting {
+				if slices.ContainsFunc(a.dependenciesOf(b, k), func(p *binder) bool { return p.failed.Load() || blocked[p] }) {
+					blocked[b] = true
+					changed = true
+					break
+				}
+			}
+		}
+	}
+	var skipped []string
+	for _, b := range pending {
+		if blocked[b] {
+			skipped = append(skipped, b.moduleSignature.String())
+		}
+	}
+	return skipped
+}
+
+// findCycles walks the graph of modules that are still waiting for data keys and returns a
+// [CycleError] for every circular dependency found. An edge runs from a waiting module to each
+// not yet configured producer, decorator or collection contributor of a key it waits for.
+//
+// The caller must hold a.mu.
+func (a *assembly) findCycles() []error {
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+	state := make(map[*binder]int)
+	var path []CycleLink
+	var cycles []error
+
+	var visit func(b *binder)
+	visit = func(b *binder) {
+		state[b] = visiting
+		for _, k := range sortedKeys(b.waiting) {
+			for _, p := range a.dependenciesOf(b, k) {
+				if p.configured.Load() {
+					continue
+				}
+				link := CycleLink{
+					ModuleID:   b.moduleSignature.String(),
+					Key:        k,
+					ProducerID: p.moduleSignature.String(),
+				}
+				switch state[p] {
+				case visiting:
+					// p is on the current path: the links from p back to b close a cycle.
+					start := len(path)
+					for i := len(path) - 1; i >= 0; i-- {
+						if path[i].ModuleID == link.ProducerID {
+							start = i
+							break
+						}
+					}
+					cycle := append(slices.Clone(path[start:]), link)
+					cycles = append(cycles, &CycleError{Cycle: cycle})
+				case unvisited:
+					path = append(path, link)
+					visit(p)
+					path = path[:len(path)-1]
+				}
+			}
+		}
+		state[b] = visited
+	}
+
+	for _, b := range a.sortedBinders() {
+		if !b.isReady() && state[b] == unvisited {
+			visit(b)
+		}
+	}
+	return cycles
+}
+
+// sortedBinders returns the assembly's binders ordered by module signature.
+//
+// The caller must hold a.mu.
+func (a *assembly) sortedBinders() []*binder {
+	binders := make([]*binder, 0, len(a.bindings))
+	for _, b := range a.bindings {
+		binders = append(binders, b)
+	}
+	slices.SortFunc(binders, func(x, y *binder) int {
+		return strings.Compare(x.moduleSignature.String(), y.moduleSignature.String())
+	})
+	return binders
+}
+
+// sortedKeys returns the keys of a DataKey-indexed map in a deterministic order.
+func sortedKeys[V any](m map[DataKey]V) []DataKey {
+	keys := make([]DataKey, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	slices.SortFunc(keys, func(x, y DataKey) int {
+		if c := strings.Compare(x.signature().String(), y.signature().String()); c != 0 {
+			return c
+		}
+		return strings.Compare(fmt.Sprint(x), fmt.Sprint(y))
+	})
+	return keys
+}
+
+// moduleIDs returns the sorted module signatures of the given binders.
+func moduleIDs(binders []*binder) []string {
+	ids := make([]string, len(binders))
+	for i, b := range binders {
+		ids[i] = b.moduleSignature.String()
+	}
+	slices.Sort(ids)
+	return ids
+}
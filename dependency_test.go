@@ -0,0 +1,200 @@
+package modz
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAssembly_Build_CycleError(t *testing.T) {
+	// m1 consumes BarKey produced by m2, which consumes FooKey produced by m1
+	m1 := &MockModule{
+		NameValue:     "m1",
+		ProducesValue: Keys(FooKey),
+		ConsumesValue: Keys(BarKey),
+	}
+	m2 := &MockModule{
+		NameValue:     "m2",
+		ProducesValue: Keys(BarKey),
+		ConsumesValue: Keys(FooKey),
+	}
+	asm, err := NewAssembly(m1, m2)
+	require.NoError(t, err)
+
+	err = asm.Build()
+	require.Error(t, err)
+
+	var cycleErr *CycleError
+	require.ErrorAs(t, err, &cycleErr)
+	require.Equal(t, []CycleLink{
+		{ModuleID: "github.com/goosz/modz:m1", Key: BarKey, ProducerID: "github.com/goosz/modz:m2"},
+		{ModuleID: "github.com/goosz/modz:m2", Key: FooKey, ProducerID: "github.com/goosz/modz:m1"},
+	}, cycleErr.Cycle)
+	require.Contains(t, err.Error(), "dependency cycle: module 'github.com/goosz/modz:m1' consumes")
+	require.Contains(t, err.Error(), "which consumes")
+
+	var missingErr *MissingProducerError
+	require.False(t, errors.As(err, &missingErr), "a cycle should not be reported as a missing producer")
+}
+
+func TestAssembly_Build_SelfCycle(t *testing.T) {
+	m1 := &MockModule{
+		NameValue:     "m1",
+		ProducesValue: Keys(FooKey),
+		ConsumesValue: Keys(FooKey),
+	}
+	asm, err := NewAssembly(m1)
+	require.NoError(t, err)
+
+	err = asm.Build()
+	var cycleErr *CycleError
+	require.ErrorAs(t, err, &cycleErr)
+	require.Equal(t, []CycleLink{
+		{ModuleID: "github.com/goosz/modz:m1", Key: FooKey, ProducerID: "github.com/goosz/modz:m1"},
+	}, cycleErr.Cycle)
+}
+
+func TestAssembly_Build_CycleThroughThreeModules(t *testing.T) {
+	// m1 -> m2 -> m3 -> m1, with m4 blocked behind the cycle
+	m1 := &MockModule{NameValue: "m1", ProducesValue: Keys(FooKey), ConsumesValue: Keys(BazKey)}
+	m2 := &MockModule{NameValue: "m2", ProducesValue: Keys(BarKey), ConsumesValue: Keys(FooKey)}
+	m3 := &MockModule{NameValue: "m3", ProducesValue: Keys(BazKey), ConsumesValue: Keys(BarKey)}
+	m4 := &MockModule{NameValue: "m4", ConsumesValue: Keys(BarKey)}
+	asm, err := NewAssembly(m4, m3, m2, m1)
+	require.NoError(t, err)
+
+	err = asm.Build()
+	var cycleErr *CycleError
+	require.ErrorAs(t, err, &cycleErr)
+	require.Len(t, cycleErr.Cycle, 3)
+	require.Equal(t, "github.com/goosz/modz:m1", cycleErr.Cycle[0].ModuleID)
+	require.Equal(t, "github.com/goosz/modz:m1", cycleErr.Cycle[2].ProducerID)
+	require.NotContains(t, err.Error(), "m4")
+}
+
+func TestAssembly_Build_MissingProducerError(t *testing.T) {
+	// m1 and m2 consume FooKey, which nobody produces; m3 is blocked behind m2
+	m1 := &MockModule{NameValue: "m1", ConsumesValue: Keys(FooKey)}
+	m2 := &MockModule{NameValue: "m2", ProducesValue: Keys(BarKey), ConsumesValue: Keys(FooKey)}
+	m3 := &MockModule{NameValue: "m3", ConsumesValue: Keys(BarKey)}
+	asm, err := NewAssembly(m1, m2, m3)
+	require.NoError(t, err)
+
+	err = asm.Build()
+	require.Error(t, err)
+
+	var missingErr *MissingProducerError
+	require.ErrorAs(t, err, &missingErr)
+	require.Equal(t, FooKey, missingErr.Key)
+	require.Equal(t, []string{"github.com/goosz/modz:m1", "github.com/goosz/modz:m2"}, missingErr.ModuleIDs)
+	require.Contains(t, err.Error(), "no module produces it")
+
+	var cycleErr *CycleError
+	require.False(t, errors.As(err, &cycleErr), "a missing producer should not be reported as a cycle")
+}
+
+func TestAssembly_Build_CycleAndMissingProducer(t *testing.T) {
+	m1 := &MockModule{NameValue: "m1", ProducesValue: Keys(FooKey), ConsumesValue: Keys(BarKey)}
+	m2 := &MockModule{NameValue: "m2", ProducesValue: Keys(BarKey), ConsumesValue: Keys(FooKey)}
+	m3 := &MockModule{NameValue: "m3", ConsumesValue: Keys(QuxKey)}
+	asm, err := NewAssembly(m1, m2, m3)
+	require.NoError(t, err)
+
+	err = asm.Build()
+	var cycleErr *CycleError
+	require.ErrorAs(t, err, &cycleErr)
+	var missingErr *MissingProducerError
+	require.ErrorAs(t, err, &missingErr)
+	require.Equal(t, QuxKey, missingErr.Key)
+}
+
+func TestAssembly_Build_AggregateErrors(t *testing.T) {
+	// failing1 and failing2 both fail; ok is independent and must still be configured;
+	// consumer depends on failing1 and downstream depends on consumer, so both are skipped.
+	okConfigured := false
+	failing1 := &MockModule{
+		NameValue:     "failing1",
+		ProducesValue: Keys(FooKey),
+		ConfigureFunc: func(b Binder) error { return errors.New("failure 1") },
+	}
+	failing2 := &MockModule{
+		NameValue:     "failing2",
+		ConfigureFunc: func(b Binder) error { return errors.New("failure 2") },
+	}
+	ok := &MockModule{
+		NameValue:     "ok",
+		ProducesValue: Keys(QuxKey),
+		ConfigureFunc: func(b Binder) error {
+			okConfigured = true
+			return QuxKey.Put(b, 1)
+		},
+	}
+	consumer := &MockModule{
+		NameValue:     "consumer",
+		ProducesValue: Keys(BarKey),
+		ConsumesValue: Keys(FooKey, QuxKey),
+	}
+	downstream := &MockModule{
+		NameValue:     "downstream",
+		ConsumesValue: Keys(BarKey),
+	}
+	orphan := &MockModule{
+		NameValue:     "orphan",
+		ConsumesValue: Keys(BazKey),
+	}
+	asm, err := NewAssemblyWithOptions([]AssemblyOption{WithAggregateErrors()},
+		failing1, failing2, ok, consumer, downstream, orphan)
+	require.NoError(t, err)
+
+	err = asm.Build()
+	require.Error(t, err)
+	require.True(t, okConfigured)
+
+	var asmErr *AssemblyError
+	require.ErrorAs(t, err, &asmErr)
+	require.Len(t, asmErr.Errors, 3)
+	require.Equal(t, []string{"github.com/goosz/modz:consumer", "github.com/goosz/modz:downstream"}, asmErr.Skipped)
+
+	var configErr *ConfigurationError
+	require.ErrorAs(t, err, &configErr)
+	var missingErr *MissingProducerError
+	require.ErrorAs(t, err, &missingErr)
+	require.Equal(t, BazKey, missingErr.Key)
+
+	require.Contains(t, err.Error(), "failure 1")
+	require.Contains(t, err.Error(), "failure 2")
+	require.Contains(t, err.Error(), "skipped because of upstream failures")
+}
+
+func TestAssembly_Build_AggregateErrors_SkippedCycle(t *testing.T) {
+	// m1 and m2 form a cycle that also depends on a failed module.
+	failing := &MockModule{
+		NameValue:     "failing",
+		ProducesValue: Keys(BazKey),
+		ConfigureFunc: func(b Binder) error { return errors.New("failure") },
+	}
+	m1 := &MockModule{NameValue: "m1", ProducesValue: Keys(FooKey), ConsumesValue: Keys(BarKey)}
+	m2 := &MockModule{NameValue: "m2", ProducesValue: Keys(BarKey), ConsumesValue: Keys(FooKey, BazKey)}
+	asm, err := NewAssemblyWithOptions([]AssemblyOption{WithAggregateErrors()}, m1, m2, failing)
+	require.NoError(t, err)
+
+	err = asm.Build()
+	var asmErr *AssemblyError
+	require.ErrorAs(t, err, &asmErr)
+	require.Equal(t, []string{"github.com/goosz/modz:m1", "github.com/goosz/modz:m2"}, asmErr.Skipped)
+}
+
+func TestNewAssemblyWithOptions_AggregateInstallErrors(t *testing.T) {
+	m1 := &MockModule{NameValue: "m1", ProducesValue: Keys(FooKey)}
+	m2 := &MockModule{NameValue: "m2", ProducesValue: Keys(FooKey)}
+	asm, err := NewAssemblyWithOptions([]AssemblyOption{WithAggregateErrors()}, m1, m1, m2, nil)
+	require.Nil(t, asm)
+
+	var asmErr *AssemblyError
+	require.ErrorAs(t, err, &asmErr)
+	require.Len(t, asmErr.Errors, 3)
+	require.Contains(t, asmErr.Errors[0].Error(), "already added")
+	require.Contains(t, asmErr.Errors[1].Error(), "duplicate producer")
+	require.Contains(t, asmErr.Errors[2].Error(), "cannot add nil module")
+}
@@ -25,6 +25,33 @@
 //     are only valid during this configuration phase; calling them outside this phase is strictly
 //     enforced and will result in an error.
 //
+// Modules that can use, but do not require, some [Data] implement [OptionalConsumer]. They are
+// configured once their optional keys are either produced or provably never will be, and read
+// them with [Data].Lookup(), which reports absence instead of returning an error.
+//
+// Expensive values can be produced lazily with [Data].Provide(), which registers a constructor
+// called once, on the first Get() of the value, instead of computing the value in Configure().
+//
+// A [Data] key declared with [WithValidator] checks every value stored under it, so that an
+// invalid value, such as an empty DSN, is reported on the module producing it rather than
+// reaching its consumers. A key declared with [WithDefault] or [WithDefaultFunc] falls back to
+// its default value when no module produces it, such as a timeout or a feature flag.
+// The modzconfig package provides a module producing [Data] values read from environment
+// variables and configuration files.
+//
+// Values that many modules contribute to are shared through [SetData] and [MapData] keys, which
+// any number of modules may declare in Produces(). Their consumers are configured once every
+// contributor has been configured, and read all contributions in a deterministic order.
+//
+// A [Decorator] wraps the value of a [Data] key produced by another module, such as a logger
+// or an HTTP handler, before its consumers see it. Decorators of the same key are configured
+// one after the other, in the order of their signatures, each reading the value left by the
+// previous one and optionally replacing it with Put().
+//
+// Modules that must be configured after others without any [Data] flowing between them, such
+// as a module seeding a database after its migrations, implement [Requirer] to name the
+// modules they require, or consume a [Marker] key produced by those modules.
+//
 // Modules can optionally embed [Singleton] to indicate they can be installed multiple times without
 // error. This is useful for modules that should be shared across multiple parts of an application.
 //
@@ -33,12 +60,21 @@
 // The framework provides robust error handling and validation during module configuration:
 //   - All configuration errors are wrapped with [ConfigurationError] to provide context about which
 //     module and operation failed
+//   - Every failure category has a sentinel error (such as [ErrUndeclaredKey], [ErrDuplicateProducer]
+//     or [ErrMissingProducer]) that can be matched with [errors.Is]; most errors are of type [*Error],
+//     which carries the module signature and [DataKey] involved
+//   - A panic in a module's Configure() is recovered and reported as a [PanicError], with the
+//     panic value and stack, wrapped in the module's [ConfigurationError]
 //   - The framework implements fail-fast behavior, tracking the first error encountered during configuration
 //   - The framework detects when modules return nil errors despite encountering configuration problems
 //   - Modules must properly handle and return errors from Binder operations (Install, Get, Put)
 //   - Missing declared dependencies are automatically detected and reported
+//   - Circular dependencies are reported as [CycleError] with the full chain of modules and keys,
+//     separately from keys that no module produces ([MissingProducerError])
 //   - Duplicate producers for the same data key are detected and reported during module installation
 //   - Data key signature clashes are detected and reported to prevent conflicts between packages
+//   - With [WithAggregateErrors], installation and Build report every error at once as an
+//     [AssemblyError], together with the modules skipped because of upstream failures
 //
 // # Module Uniqueness
 //
@@ -54,21 +90,57 @@
 // Data keys are automatically validated to ensure uniqueness and prevent conflicts:
 //   - Each data key includes package information and a process-unique serial number
 //   - The framework detects when different data keys have the same signature (name + package)
-//   - NewData() must be called from package-level var declarations to ensure proper initialization
-//     (panics if called from other contexts)
+//   - NewData(), NewSetData(), NewMapData() and NewMarker() must be called from package-level
+//     var declarations to ensure proper initialization (panics if called from other contexts)
 //   - Data keys are validated during module installation to catch configuration errors early
 //
 // # Assembly Lifecycle
 //
 // The [Assembly] is responsible for orchestrating the module lifecycle. It first builds the
 // dependency graph by inspecting all [Module]s, then configures each [Module] in dependency order.
-// The [Assembly] itself does not manage application runtime; it focuses on construction and wiring.
+// Once built, Start() and Stop() run the modules implementing [Starter] and [Stopper] in that
+// same dependency order (reversed for Stop), rolling back the modules already started if one
+// fails to start. The [Assembly] otherwise leaves the application runtime to the user.
+//
+// Optional behavior is configured by passing [AssemblyOption] values to [NewAssemblyWithOptions].
+// By default modules are configured one at a time; [WithParallelism] lets Build configure
+// independent modules concurrently, which helps when modules perform I/O during configuration.
+//
+// [WithParent] creates a child [Assembly] from a built parent, for per-tenant or per-request
+// subsystems: its modules read the parent's [Data], may produce keys that shadow or extend the
+// parent's, and the child is built and discarded without modifying the parent.
+//
+// For tests, [WithModuleOverride] installs a replacement in place of a module, such as a fake
+// database module, and [WithData] seeds a [Data] value so that the module producing it is skipped.
+// The modztest package configures a single module in isolation, recording what it produces and
+// installs.
+//
+// Outside an Assembly, a [DataStore] holds values under [Data] keys, so that the same typed keys
+// can serve as test fixtures, request-scoped bags or configuration snapshots.
+//
+// BuildContext() runs the build under a [context.Context] that modules can observe through
+// [Binder].Context(). [WithBuildTimeout] and [WithModuleTimeout] bound the whole build and each
+// module's configuration; a module still configuring when its deadline is reached is reported
+// as a [ConfigurationError], so a hung module can no longer block startup forever.
 //
 // The Build() method of [Assembly] can only be called once per Assembly instance; subsequent calls
 // will return an error. After Build() completes successfully, the [Assembly] can be used as a
 // [DataReader] to access the data values produced by modules. Data access is only available after
 // successful build completion.
 //
+// After Build(), BuildReport() returns a [BuildReport] with the time each module spent waiting
+// for its dependencies and in Configure(), and the key that unblocked it. [WithBuildObserver]
+// plugs in a [BuildObserver], such as a tracer, notified as the build progresses.
+//
+// DataEntries() lists every value held by the [Assembly] with the modules producing and
+// consuming it, for diagnostics endpoints and assertions in tests.
+//
+// [WithLogger] makes the [Assembly] log installation, scheduling, data access and build
+// outcome events to a [log/slog.Logger].
+//
+// The dependency graph of an [Assembly] can be inspected at any time with Graph(), which returns
+// a [Graph] that can be exported as Graphviz DOT, Mermaid or JSON for visualization.
+//
 // # Intended Usage
 //
 // Modz is designed for applications that benefit from modularity, clear dependency management,
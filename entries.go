@@ -0,0 +1,59 @@
+package modz
+
+import (
+	"fmt"
+)
+
+// DataEntry describes a value held by an [Assembly], as listed by its DataEntries() method.
+//
+// A DataEntry does not include the value itself, which is read with the [Data] key as usual;
+// it can be encoded as JSON, for example to serve it from a diagnostics endpoint.
+type DataEntry struct {
+	// Key is the data key holding the value.
+	Key DataKey `json:"-"`
+	// ID is the data key signature (package path and key name).
+	ID string `json:"id"`
+	// Type is the Go type of the value.
+	Type string `json:"type"`
+	// Label is the full name of the key, as in Data[T](signature#serial).
+	Label string `json:"label"`
+	// Producers lists the signatures of the modules producing the value, sorted. It holds
+	// every contributor of a [SetData] or [MapData] key.
+	Producers []string `json:"producers"`
+	// Consumers lists the signatures of the modules consuming the value, sorted, whether
+	// they require it or consume it optionally.
+	Consumers []string `json:"consumers"`
+	// Seeded is true if the value was set with [WithData].
+	Seeded bool `json:"seeded,omitempty"`
+	// Defaulted is true if the value is the default of the key, see [WithDefault].
+	Defaulted bool `json:"defaulted,omitempty"`
+}
+
+func (a *assembly) DataEntries() []DataEntry {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	binders := a.sortedBinders()
+	entries := make([]DataEntry, 0, len(a.data))
+	for _, k := range sortedKeys(a.data) {
+		entry := DataEntry{
+			Key:       k,
+			ID:        k.signature().String(),
+			Type:      keyTypeName(k),
+			Label:     fmt.Sprint(k),
+			Producers: moduleIDs(a.producersOf(k)),
+			Consumers: []string{},
+		}
+		_, entry.Seeded = a.options.seededData[k]
+		_, entry.Defaulted = a.defaulted[k]
+		for _, b := range binders {
+			_, consumed := b.consumes[k]
+			_, optional := b.optional[k]
+			if consumed || optional {
+				entry.Consumers = append(entry.Consumers, b.moduleSignature.String())
+			}
+		}
+		entries = append(entries, entry)
+	}
+	return entries
+}
@@ -0,0 +1,70 @@
+package modz
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAssembly_DataEntries(t *testing.T) {
+	producer := &MockModule{
+		NameValue:     "producer",
+		ProducesValue: Keys(FooKey, ListKey),
+		ConfigureFunc: func(b Binder) error {
+			if err := ListKey.Add(b, "x"); err != nil {
+				return err
+			}
+			return FooKey.Provide(b, func() (int, error) {
+				t.Fatal("DataEntries must not compute lazy values")
+				return 0, nil
+			})
+		},
+	}
+	contributor := &MockModule{NameValue: "contributor", ProducesValue: Keys(ListKey)}
+	consumer := &MockModule{
+		NameValue:             "consumer",
+		ConsumesValue:         Keys(ListKey),
+		OptionalConsumesValue: Keys(FooKey),
+	}
+	other := &MockModule{NameValue: "other", ConsumesValue: Keys(FooKey, QuxKey)}
+	opts := []AssemblyOption{WithData(QuxKey, 5)}
+	asm, err := NewAssemblyWithOptions(opts, producer, contributor, consumer, other)
+	require.NoError(t, err)
+	// Before Build, only the seeded value is present.
+	require.Len(t, asm.DataEntries(), 1)
+	require.NoError(t, asm.Build())
+
+	entries := asm.DataEntries()
+	require.Len(t, entries, 3)
+
+	foo := entries[0]
+	require.Equal(t, FooKey, foo.Key)
+	require.Equal(t, "github.com/goosz/modz:foo", foo.ID)
+	require.Equal(t, "int", foo.Type)
+	require.Equal(t, []string{"github.com/goosz/modz:producer"}, foo.Producers)
+	require.Equal(t, []string{"github.com/goosz/modz:consumer", "github.com/goosz/modz:other"}, foo.Consumers)
+	require.False(t, foo.Seeded)
+
+	list := entries[1]
+	require.Equal(t, "github.com/goosz/modz:list", list.ID)
+	require.Equal(t, "[]string", list.Type)
+	require.Equal(t, []string{"github.com/goosz/modz:contributor", "github.com/goosz/modz:producer"}, list.Producers)
+	require.Equal(t, []string{"github.com/goosz/modz:consumer"}, list.Consumers)
+
+	qux := entries[2]
+	require.Equal(t, "github.com/goosz/modz:qux", qux.ID)
+	require.Empty(t, qux.Producers)
+	require.True(t, qux.Seeded)
+
+	data, err := json.Marshal(qux)
+	require.NoError(t, err)
+	require.JSONEq(t, `{
+		"id": "github.com/goosz/modz:qux",
+		"type": "int",
+		"label": "`+qux.Label+`",
+		"producers": [],
+		"consumers": ["github.com/goosz/modz:other"],
+		"seeded": true
+	}`, string(data))
+}
@@ -1,6 +1,10 @@
 package modz
 
-import "fmt"
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
 
 // ConfigurationError represents an error that occurred during module configuration.
 // It provides context about which module encountered the error and what operation failed.
@@ -17,27 +21,333 @@ func (e *ConfigurationError) Error() string {
 	return fmt.Sprintf("module '%s' %s failed", e.ModuleID, e.Operation)
 }
 
+// Unwrap returns the underlying error, allowing [errors.Is] and [errors.As] to inspect it.
+func (e *ConfigurationError) Unwrap() error {
+	return e.Err
+}
+
+// Sentinel errors identifying each category of failure reported by the framework.
+//
+// Errors returned by the framework match the sentinel of their category with [errors.Is].
+// Most of them are of type [*Error], which also carries the module signature and [DataKey]
+// involved; [CycleError] and [MissingProducerError] carry the details of dependency failures.
+var (
+	// ErrPhase reports a Binder operation attempted outside the module's configuration phase.
+	ErrPhase = errors.New("operation outside configuration phase")
+	// ErrUndeclaredKey reports access to a DataKey missing from the module's Produces or Consumes.
+	ErrUndeclaredKey = errors.New("undeclared data key")
+	// ErrDuplicateModule reports a non-singleton module installed more than once.
+	ErrDuplicateModule = errors.New("duplicate module")
+	// ErrDuplicateProducer reports a DataKey declared in Produces by more than one module.
+	ErrDuplicateProducer = errors.New("duplicate producer")
+	// ErrDuplicateDeclaration reports a DataKey listed more than once in one of a module's
+	// declarations, or declared in both Consumes and OptionalConsumes, or in both Decorates
+	// and Produces, Consumes or OptionalConsumes.
+	ErrDuplicateDeclaration = errors.New("duplicate declaration")
+	// ErrIncompatibleOverride reports a module override that does not produce what the module it replaces produces.
+	ErrIncompatibleOverride = errors.New("incompatible module override")
+	// ErrSignatureClash reports two distinct DataKeys sharing the same signature.
+	ErrSignatureClash = errors.New("data key signature clash")
+	// ErrMissingProducer reports a consumed DataKey that no module produces.
+	ErrMissingProducer = errors.New("missing producer")
+	// ErrCycle reports a circular dependency between modules.
+	ErrCycle = errors.New("dependency cycle")
+	// ErrNotProduced reports a DataKey without a value, including declared keys a module did not Put.
+	ErrNotProduced = errors.New("data not produced")
+	// ErrAlreadySet reports a second value Put under the same DataKey.
+	ErrAlreadySet = errors.New("data already set")
+	// ErrTypeMismatch reports a stored value whose type does not match its Data key.
+	ErrTypeMismatch = errors.New("type mismatch")
+	// ErrInvalidValue reports a value rejected by a validator of its Data key, see WithValidator.
+	ErrInvalidValue = errors.New("invalid value")
+	// ErrInvalidArgument reports a nil Module, DataKey, DataReader or DataWriter.
+	ErrInvalidArgument = errors.New("invalid argument")
+	// ErrAlreadyBuilt reports a second call to Build.
+	ErrAlreadyBuilt = errors.New("assembly already built")
+	// ErrNotBuilt reports data access or Start on an Assembly that has not been built successfully.
+	ErrNotBuilt = errors.New("assembly not built")
+	// ErrAlreadyStarted reports a second call to Start.
+	ErrAlreadyStarted = errors.New("assembly already started")
+	// ErrNotStarted reports a call to Stop on an Assembly that is not running.
+	ErrNotStarted = errors.New("assembly not started")
+	// ErrPanic reports a module whose Configure method panicked.
+	ErrPanic = errors.New("module panicked")
+)
+
+// Error is a framework error of a known category.
+//
+// Kind is one of the sentinel errors above, and errors.Is(err, Kind) reports true. The
+// remaining fields identify what the error is about and are set when relevant.
+type Error struct {
+	// Kind is the sentinel error identifying the category of the failure.
+	Kind error
+	// ModuleID is the signature of the module involved, if any.
+	ModuleID string
+	// Key is the data key involved, if any.
+	Key DataKey
+	// Operation is the name of the operation that failed, if any.
+	Operation string
+
+	msg string
+	err error
+}
+
+func (e *Error) Error() string {
+	return e.msg
+}
+
+// Is reports whether target is the sentinel error of this error's category.
+func (e *Error) Is(target error) bool {
+	return target == e.Kind
+}
+
+// Unwrap returns the underlying cause, if any.
+func (e *Error) Unwrap() error {
+	return e.err
+}
+
 // newPhaseError creates a consistent error for phase violations
 func newPhaseError(operation string) error {
-	return fmt.Errorf("%s: can only be called during configuration phase", operation)
+	return &Error{
+		Kind:      ErrPhase,
+		Operation: operation,
+		msg:       fmt.Sprintf("%s: can only be called during configuration phase", operation),
+	}
 }
 
 // newUndeclaredKeyError creates a consistent error for undeclared key access
 func newUndeclaredKeyError(moduleName string, key DataKey, declarationType string) error {
-	return fmt.Errorf("module '%s' did not declare '%s' in %s", moduleName, key, declarationType)
+	return &Error{
+		Kind:     ErrUndeclaredKey,
+		ModuleID: moduleName,
+		Key:      key,
+		msg:      fmt.Sprintf("module '%s' did not declare '%s' in %s", moduleName, key, declarationType),
+	}
 }
 
 // newInstallError creates a consistent error for module installation failures
-func newInstallError(moduleName string, message string) error {
-	return fmt.Errorf("module '%s': %s", moduleName, message)
+func newInstallError(kind error, moduleName string, message string) error {
+	return &Error{
+		Kind:     kind,
+		ModuleID: moduleName,
+		msg:      fmt.Sprintf("module '%s': %s", moduleName, message),
+	}
+}
+
+// newDuplicateProducerError creates a consistent error for keys declared by two producers
+func newDuplicateProducerError(key DataKey, existingModule string, moduleName string) error {
+	return &Error{
+		Kind:     ErrDuplicateProducer,
+		ModuleID: moduleName,
+		Key:      key,
+		msg:      fmt.Sprintf("duplicate producer for data key '%s': modules '%s' and '%s' both declare they produce it", key, existingModule, moduleName),
+	}
+}
+
+// newDiscoveryError creates a consistent error for invalid Produces or Consumes declarations
+func newDiscoveryError(moduleName string, declarationType string, err error) error {
+	return &Error{
+		Kind:     ErrDuplicateDeclaration,
+		ModuleID: moduleName,
+		msg:      fmt.Sprintf("failed to convert %s to set: %v", strings.ToLower(declarationType), err),
+		err:      err,
+	}
+}
+
+// newOverlappingDeclarationError creates a consistent error for a key declared in two
+// declarations that exclude each other
+func newOverlappingDeclarationError(moduleName string, key DataKey, first string, second string) error {
+	return &Error{
+		Kind:     ErrDuplicateDeclaration,
+		ModuleID: moduleName,
+		Key:      key,
+		msg:      fmt.Sprintf("module '%s' declares '%s' in both %s and %s", moduleName, key, first, second),
+	}
+}
+
+// newNotProducedError creates a consistent error for a declared key a module did not produce
+func newNotProducedError(moduleName string, key DataKey) error {
+	return &Error{
+		Kind:     ErrNotProduced,
+		ModuleID: moduleName,
+		Key:      key,
+		msg:      fmt.Sprintf("module '%s' did not produce declared key '%s'", moduleName, key),
+	}
+}
+
+// newNotReturnedError creates a consistent error for a module whose Configure method exited
+// its goroutine without returning.
+func newNotReturnedError(moduleName string) error {
+	return &ConfigurationError{
+		ModuleID:  moduleName,
+		Operation: "Configure",
+		Err:       errors.New("exited without returning"),
+	}
 }
 
 // newDataOperationError creates a consistent error for data operation failures
-func newDataOperationError(key DataKey, message string) error {
-	return fmt.Errorf("data key '%s': %s", key, message)
+func newDataOperationError(kind error, key DataKey, message string) error {
+	return &Error{
+		Kind: kind,
+		Key:  key,
+		msg:  fmt.Sprintf("data key '%s': %s", key, message),
+	}
+}
+
+// newValidationError creates a consistent error for a value rejected by a validator of its key
+func newValidationError(key DataKey, err error) error {
+	return &Error{
+		Kind: ErrInvalidValue,
+		Key:  key,
+		msg:  fmt.Sprintf("data key '%s': invalid value: %v", key, err),
+		err:  err,
+	}
+}
+
+// newNilAccessorError creates a consistent error for a nil DataReader or DataWriter
+func newNilAccessorError(key DataKey, operation string) error {
+	return &Error{
+		Kind:      ErrInvalidArgument,
+		Key:       key,
+		Operation: operation,
+		msg:       fmt.Sprintf("%s: is nil", operation),
+	}
+}
+
+// newBuildStateError creates a consistent error for operations invalid in the assembly's build state
+func newBuildStateError(kind error, operation string, message string) error {
+	return &Error{
+		Kind:      kind,
+		Operation: operation,
+		msg:       fmt.Sprintf("%s: %s", operation, message),
+	}
 }
 
 // newFailFastError creates a consistent error for fail-fast behavior
 func newFailFastError(operation string, previousError error) error {
 	return fmt.Errorf("%s: failed due to previous error: %w", operation, previousError)
 }
+
+// CycleError reports a circular dependency between modules that prevents Build from completing.
+// Cycle lists each link of the chain in order; the producer of the last link is the module of the first.
+type CycleError struct {
+	Cycle []CycleLink
+}
+
+// CycleLink is a single link in a dependency cycle: the module ModuleID consumes Key,
+// which is produced by the module ProducerID. For a module requiring ProducerID (see
+// [Requirer]), Key identifies the required module rather than a [Data] key.
+type CycleLink struct {
+	ModuleID   string
+	Key        DataKey
+	ProducerID string
+}
+
+// Is reports whether target is [ErrCycle].
+func (e *CycleError) Is(target error) bool {
+	return target == ErrCycle
+}
+
+func (e *CycleError) Error() string {
+	var sb strings.Builder
+	sb.WriteString("dependency cycle: ")
+	for i, link := range e.Cycle {
+		if i > 0 {
+			sb.WriteString(", which ")
+		} else {
+			fmt.Fprintf(&sb, "module '%s' ", link.ModuleID)
+		}
+		if _, required := link.Key.(moduleKey); required {
+			fmt.Fprintf(&sb, "requires module '%s'", link.ProducerID)
+			continue
+		}
+		fmt.Fprintf(&sb, "consumes '%s' produced by module '%s'", link.Key, link.ProducerID)
+	}
+	return sb.String()
+}
+
+// MissingProducerError reports a data key that modules are waiting for but that no module produces.
+// ModuleIDs lists the modules that consume the key. A module required by other modules (see
+// [Requirer]) but never installed is reported with a Key identifying the required module.
+type MissingProducerError struct {
+	Key       DataKey
+	ModuleIDs []string
+}
+
+// Is reports whether target is [ErrMissingProducer].
+func (e *MissingProducerError) Is(target error) bool {
+	return target == ErrMissingProducer
+}
+
+func (e *MissingProducerError) Error() string {
+	if mk, ok := e.Key.(moduleKey); ok {
+		return fmt.Sprintf("module '%s': not installed (required by %s)", mk.sig, quoteAll(e.ModuleIDs))
+	}
+	return fmt.Sprintf("data key '%s': no module produces it (consumed by %s)", e.Key, quoteAll(e.ModuleIDs))
+}
+
+// AssemblyError aggregates the errors encountered while creating or building an [Assembly]
+// created with [WithAggregateErrors].
+//
+// Errors holds the individual errors, such as [ConfigurationError], [CycleError] and
+// [MissingProducerError], in the order they were encountered. Skipped lists the signatures of
+// the modules that were never configured because a module they depend on failed.
+//
+// AssemblyError supports [errors.Is] and [errors.As] on each of the aggregated errors.
+type AssemblyError struct {
+	Errors  []error
+	Skipped []string
+}
+
+func (e *AssemblyError) Error() string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "%d error(s)", len(e.Errors))
+	for _, err := range e.Errors {
+		sb.WriteString("\n\t")
+		sb.WriteString(err.Error())
+	}
+	if len(e.Skipped) > 0 {
+		fmt.Fprintf(&sb, "\n\tskipped because of upstream failures: %s", quoteAll(e.Skipped))
+	}
+	return sb.String()
+}
+
+// Unwrap returns the aggregated errors, allowing [errors.Is] and [errors.As] to inspect them.
+func (e *AssemblyError) Unwrap() []error {
+	return e.Errors
+}
+
+// PanicError reports a panic recovered from a module's Configure method. It is returned
+// wrapped in a [ConfigurationError] naming the module.
+type PanicError struct {
+	// Value is the value passed to panic.
+	Value any
+	// Stack is the stack trace of the goroutine at the time of the panic.
+	Stack []byte
+}
+
+// Is reports whether target is [ErrPanic].
+func (e *PanicError) Is(target error) bool {
+	return target == ErrPanic
+}
+
+func (e *PanicError) Error() string {
+	return fmt.Sprintf("panic: %v", e.Value)
+}
+
+// Unwrap returns the panic value if it is an error, allowing [errors.Is] and [errors.As] to inspect it.
+func (e *PanicError) Unwrap() error {
+	if err, ok := e.Value.(error); ok {
+		return err
+	}
+	return nil
+}
+
+// quoteAll formats a list of identifiers as a comma-separated list of quoted strings.
+func quoteAll(ids []string) string {
+	quoted := make([]string, len(ids))
+	for i, id := range ids {
+		quoted[i] = "'" + id + "'"
+	}
+	return strings.Join(quoted, ", ")
+}
@@ -0,0 +1,153 @@
+package modz
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestErrors_Sentinels(t *testing.T) {
+	t.Run("phase", func(t *testing.T) {
+		b, _ := newBinderTestFixture(&MockModule{NameValue: "mod"})
+		err := b.Install(&MockModule{NameValue: "other"})
+		require.ErrorIs(t, err, ErrPhase)
+		var e *Error
+		require.ErrorAs(t, err, &e)
+		require.Equal(t, "Install", e.Operation)
+	})
+
+	t.Run("undeclared key", func(t *testing.T) {
+		mod := &MockModule{
+			NameValue: "mod",
+			ConfigureFunc: func(b Binder) error {
+				_, err := FooKey.Get(b)
+				return err
+			},
+		}
+		asm, err := NewAssembly(mod)
+		require.NoError(t, err)
+		err = asm.Build()
+		require.ErrorIs(t, err, ErrUndeclaredKey)
+		var e *Error
+		require.ErrorAs(t, err, &e)
+		require.Equal(t, "github.com/goosz/modz:mod", e.ModuleID)
+		require.Equal(t, FooKey, e.Key)
+	})
+
+	t.Run("duplicate module", func(t *testing.T) {
+		m := &MockModule{NameValue: "m"}
+		_, err := NewAssembly(m, m)
+		require.ErrorIs(t, err, ErrDuplicateModule)
+		var e *Error
+		require.ErrorAs(t, err, &e)
+		require.Equal(t, "github.com/goosz/modz:m", e.ModuleID)
+	})
+
+	t.Run("duplicate producer", func(t *testing.T) {
+		_, err := NewAssembly(
+			&MockModule{NameValue: "m1", ProducesValue: Keys(FooKey)},
+			&MockModule{NameValue: "m2", ProducesValue: Keys(FooKey)},
+		)
+		require.ErrorIs(t, err, ErrDuplicateProducer)
+		var e *Error
+		require.ErrorAs(t, err, &e)
+		require.Equal(t, "github.com/goosz/modz:m2", e.ModuleID)
+		require.Equal(t, FooKey, e.Key)
+	})
+
+	t.Run("duplicate declaration", func(t *testing.T) {
+		_, err := NewAssembly(&MockModule{NameValue: "m", ConsumesValue: Keys(FooKey, FooKey)})
+		require.ErrorIs(t, err, ErrDuplicateDeclaration)
+		require.Contains(t, err.Error(), "failed to convert consumes to set")
+	})
+
+	t.Run("signature clash", func(t *testing.T) {
+		_, err := NewAssembly(
+			&MockModule{NameValue: "m1", ConsumesValue: Keys(ClashTestKey1)},
+			&MockModule{NameValue: "m2", ConsumesValue: Keys(ClashTestKey2)},
+		)
+		require.ErrorIs(t, err, ErrSignatureClash)
+		var e *Error
+		require.ErrorAs(t, err, &e)
+		require.Equal(t, ClashTestKey2, e.Key)
+	})
+
+	t.Run("missing producer and cycle", func(t *testing.T) {
+		asm, err := NewAssembly(
+			&MockModule{NameValue: "m1", ProducesValue: Keys(FooKey), ConsumesValue: Keys(BarKey)},
+			&MockModule{NameValue: "m2", ProducesValue: Keys(BarKey), ConsumesValue: Keys(FooKey)},
+			&MockModule{NameValue: "m3", ConsumesValue: Keys(BazKey)},
+		)
+		require.NoError(t, err)
+		err = asm.Build()
+		require.ErrorIs(t, err, ErrCycle)
+		require.ErrorIs(t, err, ErrMissingProducer)
+	})
+
+	t.Run("not produced", func(t *testing.T) {
+		asm, err := NewAssembly(&MockModule{NameValue: "m", ProducesValue: Keys(FooKey, BarKey)})
+		require.NoError(t, err)
+		err = asm.Build()
+		require.ErrorIs(t, err, ErrNotProduced)
+		var e *Error
+		require.ErrorAs(t, err, &e)
+		require.Equal(t, "github.com/goosz/modz:m", e.ModuleID)
+		require.Equal(t, BarKey, e.Key)
+
+		internal := asm.(*assembly)
+		_, err = internal.getDataValue(QuxKey)
+		require.ErrorIs(t, err, ErrNotProduced)
+	})
+
+	t.Run("already set", func(t *testing.T) {
+		asm, _ := NewAssembly()
+		internal := asm.(*assembly)
+		require.NoError(t, internal.putDataValue(FooKey, 1))
+		err := internal.putDataValue(FooKey, 2)
+		require.ErrorIs(t, err, ErrAlreadySet)
+	})
+
+	t.Run("type mismatch", func(t *testing.T) {
+		mock := NewMockDataReadWriter()
+		mock.Store[FooKey] = "not an int"
+		_, err := FooKey.Get(mock)
+		require.ErrorIs(t, err, ErrTypeMismatch)
+		var e *Error
+		require.ErrorAs(t, err, &e)
+		require.Equal(t, FooKey, e.Key)
+	})
+
+	t.Run("invalid argument", func(t *testing.T) {
+		_, err := NewAssembly(nil)
+		require.ErrorIs(t, err, ErrInvalidArgument)
+		_, err = FooKey.Get(nil)
+		require.ErrorIs(t, err, ErrInvalidArgument)
+		err = FooKey.Put(nil, 1)
+		require.ErrorIs(t, err, ErrInvalidArgument)
+	})
+
+	t.Run("build state", func(t *testing.T) {
+		asm, err := NewAssembly()
+		require.NoError(t, err)
+		_, err = FooKey.Get(asm)
+		require.ErrorIs(t, err, ErrNotBuilt)
+		require.NoError(t, asm.Build())
+		require.ErrorIs(t, asm.Build(), ErrAlreadyBuilt)
+	})
+}
+
+func TestErrors_FailFastPreservesCategory(t *testing.T) {
+	mod := &MockModule{
+		NameValue: "mod",
+		ConfigureFunc: func(b Binder) error {
+			_, _ = FooKey.Get(b)
+			return b.Install(&MockModule{NameValue: "other"})
+		},
+	}
+	asm, err := NewAssembly(mod)
+	require.NoError(t, err)
+	err = asm.Build()
+	require.ErrorIs(t, err, ErrUndeclaredKey)
+	require.False(t, errors.Is(err, ErrPhase))
+}
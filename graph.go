@@ -0,0 +1,238 @@
+package modz
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// Graph is a snapshot of the dependency graph held by an [Assembly].
+//
+// The graph has a node for every installed [Module] and for every [DataKey] that a module
+// produces or consumes, and an edge for every produces/consumes declaration. The modules
+// required by a module (see [Requirer]) are listed on its node. Nodes and
+// edges are sorted, so the same assembly always yields the same output.
+//
+// The JSON encoding of a Graph (see [Graph.WriteJSON]) is a stable schema:
+//
+//	{
+//	  "modules": [{"id": "<module signature>", "parent": "<module signature>", "requires": ["<module signature>"]}],
+//	  "keys":    [{"id": "<key signature>", "type": "<Go type>", "label": "<Data[T] name>", "collection": true, "default": true}],
+//	  "edges":   [{"module": "<module signature>", "key": "<key signature>", "kind": "produces|consumes|optional|decorates", "label": "<Data[T] name>"}]
+//	}
+//
+// The parent field is omitted for modules passed directly to the Assembly, the requires field
+// for modules requiring no module, the collection field is only present for [SetData],
+// [MapData] and [Marker] keys, and the default field for [Data] keys with a default value.
+type Graph struct {
+	Modules []GraphModule `json:"modules"`
+	Keys    []GraphKey    `json:"keys"`
+	Edges   []GraphEdge   `json:"edges"`
+}
+
+// GraphModule is a module node of a [Graph].
+type GraphModule struct {
+	// ID is the module signature (package path and module name).
+	ID string `json:"id"`
+	// Parent is the signature of the module that installed this module, if any.
+	Parent string `json:"parent,omitempty"`
+	// Requires lists the sorted signatures of the modules this module requires, installed
+	// or not.
+	Requires []string `json:"requires,omitempty"`
+}
+
+// GraphKey is a data key node of a [Graph].
+type GraphKey struct {
+	// ID is the data key signature (package path and key name).
+	ID string `json:"id"`
+	// Type is the Go type of the values stored under the key.
+	Type string `json:"type"`
+	// Label is the full name of the key, as in Data[T](signature#serial).
+	Label string `json:"label"`
+	// Collection is true for [SetData], [MapData] and [Marker] keys, which many modules may
+	// produce.
+	Collection bool `json:"collection,omitempty"`
+	// Default is true for [Data] keys with a default value (see [WithDefault]), which need no
+	// producer.
+	Default bool `json:"default,omitempty"`
+}
+
+// Kinds of [GraphEdge].
+const (
+	EdgeProduces  = "produces"
+	EdgeConsumes  = "consumes"
+	EdgeOptional  = "optional"  // optionally consumes, see OptionalConsumer
+	EdgeDecorates = "decorates" // see Decorator
+)
+
+// GraphEdge links a module node to a data key node of a [Graph].
+type GraphEdge struct {
+	// Module is the ID of the module node.
+	Module string `json:"module"`
+	// Key is the ID of the data key node.
+	Key string `json:"key"`
+	// Kind describes the relationship: EdgeProduces, EdgeConsumes, EdgeOptional or EdgeDecorates.
+	Kind string `json:"kind"`
+	// Label is the full name of the key, as in Data[T](signature#serial).
+	Label string `json:"label"`
+}
+
+// WriteJSON writes the graph to w using the schema documented on [Graph].
+func (g *Graph) WriteJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(g)
+}
+
+// WriteDOT writes the graph to w in Graphviz DOT format. Modules are drawn as boxes and
+// data keys as ellipses; produces and decorates edges point from a module to a key, consumes
+// edges point from a key to a module, optional edges are dashed and decorates edges are bold.
+// Dotted edges point from a required module to the modules requiring it.
+func (g *Graph) WriteDOT(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+	fmt.Fprintln(bw, "digraph modz {")
+	fmt.Fprintln(bw, "  rankdir=LR;")
+	for _, m := range g.Modules {
+		fmt.Fprintf(bw, "  %s [label=%s, shape=box];\n", strconv.Quote("module:"+m.ID), strconv.Quote(m.ID))
+	}
+	for _, k := range g.Keys {
+		fmt.Fprintf(bw, "  %s [label=%s, shape=ellipse];\n", strconv.Quote("key:"+k.ID), strconv.Quote(k.Label))
+	}
+	for _, e := range g.Edges {
+		from, to := "module:"+e.Module, "key:"+e.Key
+		if e.Kind == EdgeConsumes || e.Kind == EdgeOptional {
+			from, to = to, from
+		}
+		style := ""
+		switch e.Kind {
+		case EdgeOptional:
+			style = ", style=dashed"
+		case EdgeDecorates:
+			style = ", style=bold"
+		}
+		fmt.Fprintf(bw, "  %s -> %s [label=%s%s];\n", strconv.Quote(from), strconv.Quote(to), strconv.Quote(e.Label), style)
+	}
+	for _, m := range g.Modules {
+		for _, r := range m.Requires {
+			fmt.Fprintf(bw, "  %s -> %s [label=\"requires\", style=dotted];\n", strconv.Quote("module:"+r), strconv.Quote("module:"+m.ID))
+		}
+	}
+	fmt.Fprintln(bw, "}")
+	return bw.Flush()
+}
+
+// WriteMermaid writes the graph to w as a Mermaid flowchart, using the same node shapes
+// and edge directions as [Graph.WriteDOT].
+func (g *Graph) WriteMermaid(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+	fmt.Fprintln(bw, "flowchart LR")
+	moduleIDs := make(map[string]string, len(g.Modules))
+	for i, m := range g.Modules {
+		moduleIDs[m.ID] = fmt.Sprintf("m%d", i)
+		fmt.Fprintf(bw, "  %s[%s]\n", moduleIDs[m.ID], mermaidQuote(m.ID))
+	}
+	keyIDs := make(map[string]string, len(g.Keys))
+	for i, k := range g.Keys {
+		keyIDs[k.ID] = fmt.Sprintf("k%d", i)
+		fmt.Fprintf(bw, "  %s([%s])\n", keyIDs[k.ID], mermaidQuote(k.Label))
+	}
+	for _, e := range g.Edges {
+		from, to := moduleIDs[e.Module], keyIDs[e.Key]
+		if e.Kind == EdgeConsumes || e.Kind == EdgeOptional {
+			from, to = to, from
+		}
+		arrow := "-->"
+		switch e.Kind {
+		case EdgeOptional:
+			arrow = "-.->"
+		case EdgeDecorates:
+			arrow = "==>"
+		}
+		fmt.Fprintf(bw, "  %s %s|%s| %s\n", from, arrow, mermaidQuote(e.Label), to)
+	}
+	for _, m := range g.Modules {
+		for _, r := range m.Requires {
+			if from, ok := moduleIDs[r]; ok {
+				// A required module that is not installed has no node to link from.
+				fmt.Fprintf(bw, "  %s -.->|requires| %s\n", from, moduleIDs[m.ID])
+			}
+		}
+	}
+	return bw.Flush()
+}
+
+// mermaidQuote quotes a label for use in a Mermaid flowchart.
+func mermaidQuote(s string) string {
+	return `"` + strings.ReplaceAll(s, `"`, "#quot;") + `"`
+}
+
+// Graph returns a snapshot of the assembly's dependency graph.
+func (a *assembly) Graph() *Graph {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	g := &Graph{
+		Modules: []GraphModule{},
+		Keys:    []GraphKey{},
+		Edges:   []GraphEdge{},
+	}
+	keys := make(map[DataKey]struct{})
+	for _, b := range a.sortedBinders() {
+		node := GraphModule{ID: b.moduleSignature.String()}
+		if b.parent != nil {
+			node.Parent = b.parent.moduleSignature.String()
+		}
+		for _, k := range sortedKeys(b.requires) {
+			node.Requires = append(node.Requires, k.(moduleKey).sig.String())
+		}
+		g.Modules = append(g.Modules, node)
+		for _, k := range sortedKeys(b.produces) {
+			keys[k] = struct{}{}
+			g.Edges = append(g.Edges, newGraphEdge(b, k, EdgeProduces))
+		}
+		for _, k := range sortedKeys(b.consumes) {
+			keys[k] = struct{}{}
+			g.Edges = append(g.Edges, newGraphEdge(b, k, EdgeConsumes))
+		}
+		for _, k := range sortedKeys(b.optional) {
+			keys[k] = struct{}{}
+			g.Edges = append(g.Edges, newGraphEdge(b, k, EdgeOptional))
+		}
+		for _, k := range sortedKeys(b.decorates) {
+			keys[k] = struct{}{}
+			g.Edges = append(g.Edges, newGraphEdge(b, k, EdgeDecorates))
+		}
+	}
+	for _, k := range sortedKeys(keys) {
+		_, collection := k.(collectionKey)
+		g.Keys = append(g.Keys, GraphKey{
+			ID:         k.signature().String(),
+			Type:       keyTypeName(k),
+			Label:      fmt.Sprint(k),
+			Collection: collection,
+			Default:    hasDefault(k),
+		})
+	}
+	return g
+}
+
+// newGraphEdge creates the edge between the module bound to b and the key k.
+func newGraphEdge(b *binder, k DataKey, kind string) GraphEdge {
+	return GraphEdge{
+		Module: b.moduleSignature.String(),
+		Key:    k.signature().String(),
+		Kind:   kind,
+		Label:  fmt.Sprint(k),
+	}
+}
+
+// keyTypeName returns the name of the Go type stored under k, if known.
+func keyTypeName(k DataKey) string {
+	if t, ok := k.(interface{ typeName() string }); ok {
+		return t.typeName()
+	}
+	return fmt.Sprintf("%T", k)
+}
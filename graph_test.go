@@ -0,0 +1,160 @@
+package modz
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func newGraphTestAssembly(t *testing.T) Assembly {
+	child := &MockModule{
+		NameValue:     "child",
+		ConsumesValue: Keys(FooKey),
+	}
+	m1 := &MockModule{
+		NameValue:     "m1",
+		ProducesValue: Keys(FooKey),
+		ConfigureFunc: func(b Binder) error {
+			if err := b.Install(child); err != nil {
+				return err
+			}
+			return FooKey.Put(b, 1)
+		},
+	}
+	m2 := &MockModule{
+		NameValue:     "m2",
+		ProducesValue: Keys(BarKey),
+		ConsumesValue: Keys(FooKey),
+		ConfigureFunc: func(b Binder) error {
+			return BarKey.Put(b, 2)
+		},
+	}
+	asm, err := NewAssembly(m2, m1)
+	require.NoError(t, err)
+	return asm
+}
+
+func TestAssembly_Graph_BeforeBuild(t *testing.T) {
+	asm := newGraphTestAssembly(t)
+	g := asm.Graph()
+
+	require.Equal(t, []GraphModule{
+		{ID: "github.com/goosz/modz:m1"},
+		{ID: "github.com/goosz/modz:m2"},
+	}, g.Modules)
+	require.Len(t, g.Keys, 2)
+	require.Equal(t, "github.com/goosz/modz:bar", g.Keys[0].ID)
+	require.Equal(t, "int", g.Keys[0].Type)
+	require.Equal(t, BarKey.(*dataKey[int]).String(), g.Keys[0].Label)
+	require.Equal(t, []GraphEdge{
+		{Module: "github.com/goosz/modz:m1", Key: "github.com/goosz/modz:foo", Kind: EdgeProduces, Label: FooKey.(*dataKey[int]).String()},
+		{Module: "github.com/goosz/modz:m2", Key: "github.com/goosz/modz:bar", Kind: EdgeProduces, Label: BarKey.(*dataKey[int]).String()},
+		{Module: "github.com/goosz/modz:m2", Key: "github.com/goosz/modz:foo", Kind: EdgeConsumes, Label: FooKey.(*dataKey[int]).String()},
+	}, g.Edges)
+}
+
+func TestAssembly_Graph_AfterBuild(t *testing.T) {
+	asm := newGraphTestAssembly(t)
+	require.NoError(t, asm.Build())
+	g := asm.Graph()
+
+	require.Len(t, g.Modules, 3)
+	require.Equal(t, GraphModule{ID: "github.com/goosz/modz:child", Parent: "github.com/goosz/modz:m1"}, g.Modules[0])
+	require.Contains(t, g.Edges, GraphEdge{
+		Module: "github.com/goosz/modz:child",
+		Key:    "github.com/goosz/modz:foo",
+		Kind:   EdgeConsumes,
+		Label:  FooKey.(*dataKey[int]).String(),
+	})
+}
+
+func TestGraph_WriteJSON(t *testing.T) {
+	asm := newGraphTestAssembly(t)
+	var buf bytes.Buffer
+	require.NoError(t, asm.Graph().WriteJSON(&buf))
+
+	var decoded map[string][]map[string]string
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &decoded))
+	require.Len(t, decoded["modules"], 2)
+	require.Len(t, decoded["keys"], 2)
+	require.Len(t, decoded["edges"], 3)
+	require.Equal(t, "github.com/goosz/modz:m1", decoded["edges"][0]["module"])
+	require.Equal(t, "github.com/goosz/modz:foo", decoded["edges"][0]["key"])
+	require.Equal(t, "produces", decoded["edges"][0]["kind"])
+	require.NotContains(t, decoded["modules"][0], "parent")
+
+	// The output must be stable across calls.
+	var again bytes.Buffer
+	require.NoError(t, asm.Graph().WriteJSON(&again))
+	require.Equal(t, buf.String(), again.String())
+}
+
+func TestGraph_WriteDOT(t *testing.T) {
+	asm := newGraphTestAssembly(t)
+	var buf bytes.Buffer
+	require.NoError(t, asm.Graph().WriteDOT(&buf))
+	out := buf.String()
+
+	require.Contains(t, out, "digraph modz {")
+	require.Contains(t, out, `"module:github.com/goosz/modz:m1" [label="github.com/goosz/modz:m1", shape=box];`)
+	require.Contains(t, out, `"key:github.com/goosz/modz:foo" [label="Data[int](github.com/goosz/modz:foo#`)
+	require.Contains(t, out, `"module:github.com/goosz/modz:m1" -> "key:github.com/goosz/modz:foo" [label="Data[int]`)
+	require.Contains(t, out, `"key:github.com/goosz/modz:foo" -> "module:github.com/goosz/modz:m2" [label="Data[int]`)
+}
+
+func TestGraph_WriteMermaid(t *testing.T) {
+	asm := newGraphTestAssembly(t)
+	var buf bytes.Buffer
+	require.NoError(t, asm.Graph().WriteMermaid(&buf))
+	out := buf.String()
+
+	require.Contains(t, out, "flowchart LR")
+	require.Contains(t, out, `m0["github.com/goosz/modz:m1"]`)
+	require.Contains(t, out, `k1(["Data[int](github.com/goosz/modz:foo#`)
+	require.Contains(t, out, `m0 -->|"Data[int](github.com/goosz/modz:foo#`)
+	require.Contains(t, out, `k1 -->|"Data[int](github.com/goosz/modz:foo#`)
+}
+
+func TestMermaidQuote(t *testing.T) {
+	require.Equal(t, `"a#quot;b"`, mermaidQuote(`a"b`))
+}
+
+func TestAssembly_Graph_OptionalEdges(t *testing.T) {
+	m := &MockModule{NameValue: "m", OptionalConsumesValue: Keys(FooKey)}
+	asm, err := NewAssembly(m)
+	require.NoError(t, err)
+	g := asm.Graph()
+	require.Equal(t, []GraphEdge{
+		{Module: "github.com/goosz/modz:m", Key: "github.com/goosz/modz:foo", Kind: EdgeOptional, Label: FooKey.(*dataKey[int]).String()},
+	}, g.Edges)
+
+	var dot, mermaid bytes.Buffer
+	require.NoError(t, g.WriteDOT(&dot))
+	require.Contains(t, dot.String(), "style=dashed")
+	require.NoError(t, g.WriteMermaid(&mermaid))
+	require.Contains(t, mermaid.String(), "k0 -.->")
+}
+
+func TestAssembly_Graph_CollectionKeys(t *testing.T) {
+	m := &MockModule{NameValue: "m", ProducesValue: Keys(ListKey, FooKey)}
+	asm, err := NewAssembly(m)
+	require.NoError(t, err)
+	g := asm.Graph()
+	require.Len(t, g.Keys, 2)
+	require.False(t, g.Keys[0].Collection)
+	require.Equal(t, "github.com/goosz/modz:list", g.Keys[1].ID)
+	require.True(t, g.Keys[1].Collection)
+}
+
+func TestAssembly_Graph_DefaultKeys(t *testing.T) {
+	m := &MockModule{NameValue: "m", ConsumesValue: Keys(RetriesKey, FooKey)}
+	asm, err := NewAssembly(m)
+	require.NoError(t, err)
+	g := asm.Graph()
+	require.Len(t, g.Keys, 2)
+	require.False(t, g.Keys[0].Default)
+	require.Equal(t, "github.com/goosz/modz:retries", g.Keys[1].ID)
+	require.True(t, g.Keys[1].Default)
+}
@@ -0,0 +1,104 @@
+package modz
+
+import (
+	"context"
+	"errors"
+	"slices"
+)
+
+// Starter is implemented by modules that need to start runtime work, such as serving requests
+// or running background jobs, once the [Assembly] is built.
+//
+// The [Assembly]'s Start() calls Start() on every Starter module, in the order in which the
+// modules were configured by Build(): a module is started after the modules producing the
+// [Data] it consumes.
+type Starter interface {
+	Module
+
+	// Start starts the module's runtime work. It should return once the work is started,
+	// leaving long-running work to goroutines stopped by the module's Stop(), if any.
+	Start(ctx context.Context) error
+}
+
+// Stopper is implemented by modules that need to release runtime resources when the
+// application shuts down.
+//
+// The [Assembly]'s Stop() calls Stop() on every Stopper module that was reached by Start(),
+// in the reverse of the order in which they were started: a module is stopped before the
+// modules producing the [Data] it consumes.
+type Stopper interface {
+	Module
+
+	// Stop stops the module's runtime work and releases its resources.
+	Stop(ctx context.Context) error
+}
+
+// lifecycle tracks the progress of an assembly's Start and Stop.
+type lifecycle struct {
+	// started is the number of configured modules, in configuration order, that Start has
+	// reached; it is zero again once the assembly has been stopped.
+	started int
+	// running is true between a successful Start and the following Stop.
+	running bool
+	// startCalled is true after Start has been called once.
+	startCalled bool
+}
+
+func (a *assembly) Start(ctx context.Context) error {
+	if !a.buildCompleted.Load() {
+		return newBuildStateError(ErrNotBuilt, "Start", "can only be called after Build has completed successfully")
+	}
+	a.lifecycleMu.Lock()
+	defer a.lifecycleMu.Unlock()
+	if a.lifecycle.startCalled {
+		return newBuildStateError(ErrAlreadyStarted, "Start", "can only be called once")
+	}
+	a.lifecycle.startCalled = true
+
+	for i, b := range a.order {
+		if s, ok := b.module.(Starter); ok {
+			err := ctx.Err()
+			if err == nil {
+				err = s.Start(ctx)
+			}
+			if err != nil {
+				startErr := &ConfigurationError{ModuleID: b.moduleSignature.String(), Operation: "Start", Err: err}
+				// Roll back the modules already started, even though ctx may be done.
+				stopErrs := a.stopModules(context.WithoutCancel(ctx), a.order[:i])
+				return errors.Join(append([]error{startErr}, stopErrs...)...)
+			}
+		}
+		a.lifecycle.started = i + 1
+	}
+	a.lifecycle.running = true
+	return nil
+}
+
+func (a *assembly) Stop(ctx context.Context) error {
+	a.lifecycleMu.Lock()
+	defer a.lifecycleMu.Unlock()
+	if !a.lifecycle.running {
+		return newBuildStateError(ErrNotStarted, "Stop", "can only be called after Start has completed successfully")
+	}
+	a.lifecycle.running = false
+	errs := a.stopModules(ctx, a.order[:a.lifecycle.started])
+	a.lifecycle.started = 0
+	return errors.Join(errs...)
+}
+
+// stopModules calls Stop() on the Stopper modules among binders, in reverse order. Every
+// module is stopped even if stopping another one fails; the errors are returned in the order
+// they occurred.
+func (a *assembly) stopModules(ctx context.Context, binders []*binder) []error {
+	var errs []error
+	for _, b := range slices.Backward(binders) {
+		s, ok := b.module.(Stopper)
+		if !ok {
+			continue
+		}
+		if err := s.Stop(ctx); err != nil {
+			errs = append(errs, &ConfigurationError{ModuleID: b.moduleSignature.String(), Operation: "Stop", Err: err})
+		}
+	}
+	return errs
+}
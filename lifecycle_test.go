@@ -0,0 +1,173 @@
+package modz
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// newLifecycleChain returns three lifecycle modules where c consumes from b, which consumes
+// from a, recording their Start and Stop calls in events.
+func newLifecycleChain(events *[]string) (a, b, c *MockLifecycleModule) {
+	record := func(name string) (func(context.Context) error, func(context.Context) error) {
+		return func(context.Context) error {
+				*events = append(*events, "start "+name)
+				return nil
+			}, func(context.Context) error {
+				*events = append(*events, "stop "+name)
+				return nil
+			}
+	}
+	a = &MockLifecycleModule{MockModule: MockModule{
+		NameValue:     "a",
+		ProducesValue: Keys(FooKey),
+		ConfigureFunc: func(b Binder) error { return FooKey.Put(b, 1) },
+	}}
+	a.StartFunc, a.StopFunc = record("a")
+	b = &MockLifecycleModule{MockModule: MockModule{
+		NameValue:     "b",
+		ProducesValue: Keys(BarKey),
+		ConsumesValue: Keys(FooKey),
+		ConfigureFunc: func(b Binder) error { return BarKey.Put(b, 2) },
+	}}
+	b.StartFunc, b.StopFunc = record("b")
+	c = &MockLifecycleModule{MockModule: MockModule{
+		NameValue:     "c",
+		ConsumesValue: Keys(BarKey),
+	}}
+	c.StartFunc, c.StopFunc = record("c")
+	return a, b, c
+}
+
+func TestAssembly_StartStop(t *testing.T) {
+	var events []string
+	a, b, c := newLifecycleChain(&events)
+	plain := &MockModule{NameValue: "plain"}
+	asm, err := NewAssembly(c, plain, b, a)
+	require.NoError(t, err)
+	require.NoError(t, asm.Build())
+
+	require.NoError(t, asm.Start(context.Background()))
+	require.Equal(t, []string{"start a", "start b", "start c"}, events)
+
+	events = nil
+	require.NoError(t, asm.Stop(context.Background()))
+	require.Equal(t, []string{"stop c", "stop b", "stop a"}, events)
+}
+
+func TestAssembly_Start_RollsBackOnFailure(t *testing.T) {
+	var events []string
+	a, b, c := newLifecycleChain(&events)
+	c.StartFunc = func(context.Context) error { return errors.New("start failure") }
+	asm, err := NewAssembly(a, b, c)
+	require.NoError(t, err)
+	require.NoError(t, asm.Build())
+
+	err = asm.Start(context.Background())
+	require.Error(t, err)
+	require.Equal(t, []string{"start a", "start b", "stop b", "stop a"}, events)
+
+	var configErr *ConfigurationError
+	require.ErrorAs(t, err, &configErr)
+	require.Equal(t, "github.com/goosz/modz:c", configErr.ModuleID)
+	require.Equal(t, "Start", configErr.Operation)
+	require.Contains(t, err.Error(), "start failure")
+
+	require.ErrorIs(t, asm.Stop(context.Background()), ErrNotStarted)
+}
+
+func TestAssembly_Start_CanceledContext(t *testing.T) {
+	var events []string
+	a, b, c := newLifecycleChain(&events)
+	ctx, cancel := context.WithCancel(context.Background())
+	b.StartFunc = func(context.Context) error {
+		events = append(events, "start b")
+		cancel()
+		return nil
+	}
+	var stopCtxErr error
+	a.StopFunc = func(ctx context.Context) error {
+		events = append(events, "stop a")
+		stopCtxErr = ctx.Err()
+		return nil
+	}
+	asm, err := NewAssembly(a, b, c)
+	require.NoError(t, err)
+	require.NoError(t, asm.Build())
+
+	err = asm.Start(ctx)
+	require.ErrorIs(t, err, context.Canceled)
+	require.Equal(t, []string{"start a", "start b", "stop b", "stop a"}, events)
+	require.NoError(t, stopCtxErr, "rollback must not use the canceled context")
+}
+
+func TestAssembly_Start_CanceledContextNamesStarter(t *testing.T) {
+	// plain is configured first but has no Start method: the error must name the Starter.
+	plain := &MockModule{NameValue: "plain"}
+	starter := &MockLifecycleModule{MockModule: MockModule{NameValue: "starter"}}
+	asm, err := NewAssembly(plain, starter)
+	require.NoError(t, err)
+	require.NoError(t, asm.Build())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	err = asm.Start(ctx)
+	require.ErrorIs(t, err, context.Canceled)
+	var configErr *ConfigurationError
+	require.ErrorAs(t, err, &configErr)
+	require.Equal(t, "github.com/goosz/modz:starter", configErr.ModuleID)
+
+	// Without any Starter, there is nothing to cancel.
+	asm, err = NewAssembly(plain)
+	require.NoError(t, err)
+	require.NoError(t, asm.Build())
+	require.NoError(t, asm.Start(ctx))
+}
+
+func TestAssembly_Stop_ContinuesAfterError(t *testing.T) {
+	var events []string
+	a, b, c := newLifecycleChain(&events)
+	b.StopFunc = func(context.Context) error { return errors.New("stop failure") }
+	asm, err := NewAssembly(a, b, c)
+	require.NoError(t, err)
+	require.NoError(t, asm.Build())
+	require.NoError(t, asm.Start(context.Background()))
+
+	events = nil
+	err = asm.Stop(context.Background())
+	require.Error(t, err)
+	require.Equal(t, []string{"stop c", "stop a"}, events)
+	var configErr *ConfigurationError
+	require.ErrorAs(t, err, &configErr)
+	require.Equal(t, "github.com/goosz/modz:b", configErr.ModuleID)
+	require.Equal(t, "Stop", configErr.Operation)
+
+	require.ErrorIs(t, asm.Stop(context.Background()), ErrNotStarted)
+}
+
+func TestAssembly_Start_BeforeBuild(t *testing.T) {
+	asm, err := NewAssembly(&MockModule{NameValue: "m"})
+	require.NoError(t, err)
+	require.ErrorIs(t, asm.Start(context.Background()), ErrNotBuilt)
+	require.ErrorIs(t, asm.Stop(context.Background()), ErrNotStarted)
+}
+
+func TestAssembly_Start_Twice(t *testing.T) {
+	asm, err := NewAssembly(&MockModule{NameValue: "m"})
+	require.NoError(t, err)
+	require.NoError(t, asm.Build())
+	require.NoError(t, asm.Start(context.Background()))
+	require.ErrorIs(t, asm.Start(context.Background()), ErrAlreadyStarted)
+}
+
+func TestAssembly_Start_ParallelOrder(t *testing.T) {
+	var events []string
+	a, b, c := newLifecycleChain(&events)
+	asm, err := NewAssemblyWithOptions([]AssemblyOption{WithParallelism(4)}, c, b, a)
+	require.NoError(t, err)
+	require.NoError(t, asm.Build())
+	require.NoError(t, asm.Start(context.Background()))
+	require.Equal(t, []string{"start a", "start b", "start c"}, events)
+}
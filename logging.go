@@ -0,0 +1,50 @@
+package modz
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+)
+
+// WithLogger makes the [Assembly] log its activity to logger.
+//
+// Installation, discovery, scheduling, data access through a [Binder] and singleton
+// deduplication are logged at the [slog.LevelDebug] level; the completion of Build() at the
+// [slog.LevelInfo] level, or its failure at the [slog.LevelError] level. Events carry the
+// signature of the module involved as the "module" attribute and the signature of the data
+// key involved as the "key" attribute.
+//
+// By default nothing is logged. Returns an error from [NewAssemblyWithOptions] if logger is nil.
+func WithLogger(logger *slog.Logger) AssemblyOption {
+	return func(o *assemblyOptions) error {
+		if logger == nil {
+			return fmt.Errorf("WithLogger: logger must not be nil")
+		}
+		o.logger = logger
+		return nil
+	}
+}
+
+// log emits an event at the given level with the given attributes.
+func (a *assembly) log(ctx context.Context, level slog.Level, msg string, attrs ...slog.Attr) {
+	a.options.logger.LogAttrs(ctx, level, msg, attrs...)
+}
+
+// moduleAttr returns the attribute identifying the module bound to b.
+func moduleAttr(b *binder) slog.Attr {
+	return slog.String("module", b.moduleSignature.String())
+}
+
+// keyAttr returns the attribute identifying the data key k.
+func keyAttr(k DataKey) slog.Attr {
+	return slog.String("key", k.signature().String())
+}
+
+// keysAttr returns an attribute listing the signatures of the given keys in a deterministic order.
+func keysAttr(name string, keys map[DataKey]struct{}) slog.Attr {
+	sigs := make([]string, 0, len(keys))
+	for _, k := range sortedKeys(keys) {
+		sigs = append(sigs, k.signature().String())
+	}
+	return slog.Any(name, sigs)
+}
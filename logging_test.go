@@ -0,0 +1,125 @@
+package modz
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// newTestLogger returns a logger writing JSON events at every level to buf.
+func newTestLogger(buf *bytes.Buffer) *slog.Logger {
+	return slog.New(slog.NewJSONHandler(buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+}
+
+// logEvents decodes the JSON events written to buf.
+func logEvents(t *testing.T, buf *bytes.Buffer) []map[string]any {
+	t.Helper()
+	var events []map[string]any
+	dec := json.NewDecoder(buf)
+	for dec.More() {
+		var event map[string]any
+		require.NoError(t, dec.Decode(&event))
+		events = append(events, event)
+	}
+	return events
+}
+
+// findEvent returns the first event with the given message and module attribute.
+func findEvent(events []map[string]any, msg, module string) map[string]any {
+	for _, e := range events {
+		if e["msg"] == msg && (module == "" || e["module"] == module) {
+			return e
+		}
+	}
+	return nil
+}
+
+func TestWithLogger(t *testing.T) {
+	var buf bytes.Buffer
+	child := &MockSingletonModule{NameValue: "child"}
+	producer := &MockModule{
+		NameValue:     "producer",
+		ProducesValue: Keys(FooKey),
+		ConfigureFunc: func(b Binder) error {
+			if err := b.Install(child); err != nil {
+				return err
+			}
+			return FooKey.Put(b, 1)
+		},
+	}
+	consumer := &MockModule{
+		NameValue:     "consumer",
+		ConsumesValue: Keys(FooKey),
+		ConfigureFunc: func(b Binder) error {
+			_, err := FooKey.Get(b)
+			return err
+		},
+	}
+	opts := []AssemblyOption{WithLogger(newTestLogger(&buf))}
+	asm, err := NewAssemblyWithOptions(opts, producer, consumer, child)
+	require.NoError(t, err)
+	require.NoError(t, asm.Build())
+
+	events := logEvents(t, &buf)
+	discovered := findEvent(events, "module discovered", "github.com/goosz/modz:consumer")
+	require.NotNil(t, discovered)
+	require.Equal(t, []any{"github.com/goosz/modz:foo"}, discovered["consumes"])
+
+	require.NotNil(t, findEvent(events, "module installed", "github.com/goosz/modz:producer"))
+	require.NotNil(t, findEvent(events, "singleton module already installed", "github.com/goosz/modz:child"))
+
+	scheduled := findEvent(events, "module scheduled", "github.com/goosz/modz:consumer")
+	require.NotNil(t, scheduled)
+	require.Equal(t, "github.com/goosz/modz:foo", scheduled["unblocked_by"])
+
+	put := findEvent(events, "data put", "github.com/goosz/modz:producer")
+	require.NotNil(t, put)
+	require.Equal(t, "github.com/goosz/modz:foo", put["key"])
+	require.NotNil(t, findEvent(events, "data get", "github.com/goosz/modz:consumer"))
+
+	completed := findEvent(events, "build completed", "")
+	require.NotNil(t, completed)
+	require.Equal(t, "INFO", completed["level"])
+	require.EqualValues(t, 3, completed["modules"])
+}
+
+func TestWithLogger_InstallParent(t *testing.T) {
+	var buf bytes.Buffer
+	child := &MockModule{NameValue: "child"}
+	parent := &MockModule{
+		NameValue:     "parent",
+		ConfigureFunc: func(b Binder) error { return b.Install(child) },
+	}
+	asm, err := NewAssemblyWithOptions([]AssemblyOption{WithLogger(newTestLogger(&buf))}, parent)
+	require.NoError(t, err)
+	require.NoError(t, asm.Build())
+
+	installed := findEvent(logEvents(t, &buf), "module installed", "github.com/goosz/modz:child")
+	require.NotNil(t, installed)
+	require.Equal(t, "github.com/goosz/modz:parent", installed["parent"])
+}
+
+func TestWithLogger_BuildFailed(t *testing.T) {
+	var buf bytes.Buffer
+	failing := &MockModule{
+		NameValue:     "failing",
+		ConfigureFunc: func(b Binder) error { return errors.New("failure") },
+	}
+	asm, err := NewAssemblyWithOptions([]AssemblyOption{WithLogger(newTestLogger(&buf))}, failing)
+	require.NoError(t, err)
+	require.Error(t, asm.Build())
+
+	failed := findEvent(logEvents(t, &buf), "build failed", "")
+	require.NotNil(t, failed)
+	require.Equal(t, "ERROR", failed["level"])
+	require.Contains(t, failed["error"], "failure")
+}
+
+func TestWithLogger_Nil(t *testing.T) {
+	_, err := NewAssemblyWithOptions([]AssemblyOption{WithLogger(nil)})
+	require.Error(t, err)
+}
@@ -1,5 +1,7 @@
 package modz
 
+import "context"
+
 var (
 	ProducedKey = NewData[string]("produced")
 	ConsumedKey = NewData[int]("consumed")
@@ -9,19 +11,39 @@ var (
 	// Keys for registry validation testing
 	ClashTestKey1 = NewData[int]("clash-test-1")
 	ClashTestKey2 = NewData[int]("clash-test-1") // Same signature as ClashTestKey1
+
+	// Additional keys for dependency graph testing
+	BazKey = NewData[int]("baz")
+	QuxKey = NewData[int]("qux")
+
+	// Keys for collection testing
+	ListKey  = NewSetData[string]("list")
+	IndexKey = NewMapData[string, int]("index")
+
+	// Keys for requirement testing
+	MigratedKey = NewMarker("migrated")
+
+	// Keys for settle ordering testing
+	TagsKey = NewSetData[string]("tags")
+
+	// Keys for default value testing
+	RetriesKey = NewData("retries", WithDefault(3))
 )
 
 // MockModule is a minimal implementation of Module for unit tests.
+// It also implements OptionalConsumer; a nil OptionalConsumesValue declares no optional keys.
 type MockModule struct {
-	NameValue     string
-	ProducesValue DataKeys
-	ConsumesValue DataKeys
-	ConfigureFunc func(Binder) error
+	NameValue             string
+	ProducesValue         DataKeys
+	ConsumesValue         DataKeys
+	OptionalConsumesValue DataKeys
+	ConfigureFunc         func(Binder) error
 }
 
-func (m *MockModule) Name() string       { return m.NameValue }
-func (m *MockModule) Produces() DataKeys { return m.ProducesValue }
-func (m *MockModule) Consumes() DataKeys { return m.ConsumesValue }
+func (m *MockModule) Name() string               { return m.NameValue }
+func (m *MockModule) Produces() DataKeys         { return m.ProducesValue }
+func (m *MockModule) Consumes() DataKeys         { return m.ConsumesValue }
+func (m *MockModule) OptionalConsumes() DataKeys { return m.OptionalConsumesValue }
 func (m *MockModule) Configure(binder Binder) error {
 	if m.ConfigureFunc != nil {
 		return m.ConfigureFunc(binder)
@@ -47,3 +69,40 @@ func (m *MockSingletonModule) Configure(binder Binder) error {
 	}
 	return nil
 }
+
+// MockLifecycleModule is a MockModule that also implements Starter and Stopper.
+type MockLifecycleModule struct {
+	MockModule
+	StartFunc func(context.Context) error
+	StopFunc  func(context.Context) error
+}
+
+func (m *MockLifecycleModule) Start(ctx context.Context) error {
+	if m.StartFunc != nil {
+		return m.StartFunc(ctx)
+	}
+	return nil
+}
+
+func (m *MockLifecycleModule) Stop(ctx context.Context) error {
+	if m.StopFunc != nil {
+		return m.StopFunc(ctx)
+	}
+	return nil
+}
+
+// MockDecoratorModule is a MockModule that also implements Decorator.
+type MockDecoratorModule struct {
+	MockModule
+	DecoratesValue DataKeys
+}
+
+func (m *MockDecoratorModule) Decorates() DataKeys { return m.DecoratesValue }
+
+// MockRequirerModule is a MockModule that also implements Requirer.
+type MockRequirerModule struct {
+	MockModule
+	RequiresValue []Module
+}
+
+func (m *MockRequirerModule) Requires() []Module { return m.RequiresValue }
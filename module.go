@@ -65,7 +65,9 @@ type Module interface {
 	// Configure should be fast to execute and should not perform any heavy work
 	// such as starting services, opening connections, or loading large amounts
 	// of data. Such initialization should be deferred to runtime after the
-	// [Assembly] Build() has completed.
+	// [Assembly] Build() has completed, for example by implementing [Starter].
+	// Modules that may block during configuration should observe the [Binder]'s
+	// Context() and return its error once it is done.
 	//
 	// **Error Handling Requirements:**
 	// - Configure MUST return any errors encountered from Binder operations (Install, Get, Put)
@@ -78,6 +80,24 @@ type Module interface {
 	Configure(Binder) error
 }
 
+// OptionalConsumer is implemented by modules that can use, but do not require, some [Data].
+//
+// During the module's discovery phase, the [Assembly] calls OptionalConsumes() in addition to
+// Consumes(). The module is configured once every consumed [DataKey] has been produced and
+// every optionally consumed [DataKey] has either been produced or will provably never be: no
+// module produces it (and no module can be installed anymore), or its producer failed.
+//
+// During configuration, optionally consumed keys are read with [Data].Lookup(), which reports
+// whether the value is present instead of returning an error. A key must not be declared in
+// both Consumes() and OptionalConsumes(). Like Consumes(), OptionalConsumes() must be
+// deterministic.
+type OptionalConsumer interface {
+	Module
+
+	// OptionalConsumes returns the [DataKey]s that this module uses if they are produced.
+	OptionalConsumes() DataKeys
+}
+
 // Singleton is a marker interface that can be embedded in modules to indicate
 // they will be silently ignored when installed multiple times. All modules
 // can only be installed once per assembly, but singleton modules won't
@@ -0,0 +1,415 @@
+// Package modzcheck defines an Analyzer that checks the [modz.Module] implementations of a
+// package against the data keys their Configure methods use.
+//
+// The [modz.Assembly] only reports a module reading a key it did not declare in Consumes(),
+// writing a key it did not declare in Produces(), or not producing a declared key when Build
+// runs. The analyzer reports these mistakes statically: for every type implementing
+// [modz.Module], it compares the keys returned by Produces(), Consumes(), OptionalConsumes()
+// and Decorates() with the Get, Lookup, Put, Provide and Add calls made on package-level data
+// keys in the body of Configure, and reports:
+//   - a key read in Configure but declared in none of Consumes(), OptionalConsumes() and
+//     Decorates();
+//   - a key written in Configure but declared in neither Produces() nor Decorates();
+//   - a key declared in Produces() but never written, except [modz.SetData] and
+//     [modz.MapData] keys, to which contributing is optional, and [modz.Marker] keys, which
+//     have no value;
+//   - a key declared in Consumes() or OptionalConsumes() but never read, except
+//     [modz.Marker] keys.
+//
+// The analysis is deliberately conservative. Declarations are only checked when the
+// method is declared on the module type itself, rather than promoted from an embedded type,
+// and returns nil, a [modz.Keys] call or a [modz.DataKeys] literal listing package-level
+// keys; and unused declarations are only reported when the [modz.Binder] does not escape
+// Configure, for example to a helper function that may use the keys itself.
+//
+// The analyzer can be run with go vet through the modzcheck command:
+//
+//	go install github.com/goosz/modz/cmd/modzcheck@latest
+//	go vet -vettool=$(which modzcheck) ./...
+package modzcheck
+
+import (
+	"go/ast"
+	"go/types"
+	"slices"
+
+	"golang.org/x/tools/go/analysis"
+)
+
+// modzPath is the import path of the modz package.
+const modzPath = "github.com/goosz/modz"
+
+// Analyzer checks modz.Module implementations against the data keys used by their Configure methods.
+var Analyzer = &analysis.Analyzer{
+	Name: "modzcheck",
+	Doc:  "check that modz modules declare the data keys used by Configure in Produces and Consumes",
+	URL:  "https://pkg.go.dev/github.com/goosz/modz/modzcheck",
+	Run:  run,
+}
+
+// keyMethods maps the methods of modz data keys to whether they write the key.
+var keyMethods = map[string]bool{
+	"Get":     false,
+	"Lookup":  false,
+	"Put":     true,
+	"Provide": true,
+	"Add":     true,
+}
+
+func run(pass *analysis.Pass) (any, error) {
+	modz := findModz(pass.Pkg)
+	if modz == nil {
+		return nil, nil
+	}
+	c := &checker{
+		pass:    pass,
+		modz:    modz,
+		module:  lookupInterface(modz, "Module"),
+		dataKey: lookupInterface(modz, "DataKey"),
+	}
+	if c.module == nil || c.dataKey == nil {
+		return nil, nil
+	}
+
+	// Collect the methods declared on each named type, in source order.
+	var typeNames []*types.TypeName
+	methods := make(map[*types.TypeName]map[string]*ast.FuncDecl)
+	for _, file := range pass.Files {
+		for _, decl := range file.Decls {
+			fd, ok := decl.(*ast.FuncDecl)
+			if !ok || fd.Recv == nil || fd.Body == nil {
+				continue
+			}
+			tn := receiverTypeName(pass, fd)
+			if tn == nil {
+				continue
+			}
+			if methods[tn] == nil {
+				methods[tn] = make(map[string]*ast.FuncDecl)
+				typeNames = append(typeNames, tn)
+			}
+			methods[tn][fd.Name.Name] = fd
+		}
+	}
+	for _, tn := range typeNames {
+		if c.isModule(tn) {
+			c.checkModule(tn, methods[tn])
+		}
+	}
+	return nil, nil
+}
+
+// checker holds the state of the analysis of a package.
+type checker struct {
+	pass    *analysis.Pass
+	modz    *types.Package
+	module  *types.Interface
+	dataKey *types.Interface
+}
+
+// declaration is a data key listed by Produces, Consumes, OptionalConsumes or Decorates.
+type declaration struct {
+	key  *types.Var
+	expr ast.Expr
+}
+
+// use is a call to a data key method in Configure.
+type use struct {
+	key   *types.Var
+	write bool
+	call  *ast.CallExpr
+}
+
+// checkModule reports the mismatches between the declarations of a module and its Configure method.
+func (c *checker) checkModule(tn *types.TypeName, methods map[string]*ast.FuncDecl) {
+	configure := methods["Configure"]
+	if configure == nil {
+		return
+	}
+	produces, producesKnown := c.declaredKeys(methods["Produces"])
+	consumes, consumesKnown := c.declaredKeys(methods["Consumes"])
+	optional, optionalKnown := c.optionalDeclaredKeys(tn, methods, "OptionalConsumes")
+	// The keys a decorator decorates are both read and written by Configure, and are not
+	// reported if unused: a decorator may leave the value unchanged.
+	decorates, decoratesKnown := c.optionalDeclaredKeys(tn, methods, "Decorates")
+	uses, complete := c.keyUses(configure)
+
+	name := tn.Name()
+	for _, u := range uses {
+		switch {
+		case u.write && producesKnown && decoratesKnown && !declares(produces, u.key) && !declares(decorates, u.key):
+			c.pass.Reportf(u.call.Pos(), "Configure of %s writes %s, which is not declared in Produces()", name, c.keyName(u.key))
+		case !u.write && consumesKnown && optionalKnown && decoratesKnown &&
+			!declares(consumes, u.key) && !declares(optional, u.key) && !declares(decorates, u.key):
+			c.pass.Reportf(u.call.Pos(), "Configure of %s reads %s, which is not declared in Consumes()", name, c.keyName(u.key))
+		}
+	}
+	if !complete {
+		return
+	}
+	if producesKnown {
+		for _, d := range produces {
+			if !c.isKeyType(d.key, "SetData", "MapData", "Marker") && !used(uses, d.key, true) {
+				c.pass.Reportf(d.expr.Pos(), "%s is declared in Produces() of %s but never written by Configure", c.keyName(d.key), name)
+			}
+		}
+	}
+	if consumesKnown {
+		for _, d := range consumes {
+			if !c.isKeyType(d.key, "Marker") && !used(uses, d.key, false) {
+				c.pass.Reportf(d.expr.Pos(), "%s is declared in Consumes() of %s but never read by Configure", c.keyName(d.key), name)
+			}
+		}
+	}
+	if optionalKnown {
+		for _, d := range optional {
+			if !c.isKeyType(d.key, "Marker") && !used(uses, d.key, false) {
+				c.pass.Reportf(d.expr.Pos(), "%s is declared in OptionalConsumes() of %s but never read by Configure", c.keyName(d.key), name)
+			}
+		}
+	}
+}
+
+// declaredKeys returns the keys returned by a Produces, Consumes, OptionalConsumes or
+// Decorates method, and whether they could be determined statically.
+func (c *checker) declaredKeys(fd *ast.FuncDecl) ([]declaration, bool) {
+	if fd == nil {
+		// The method is promoted from an embedded type, or declared elsewhere.
+		return nil, false
+	}
+	var decls []declaration
+	known := true
+	ast.Inspect(fd.Body, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.FuncLit:
+			return false
+		case *ast.ReturnStmt:
+			if len(n.Results) != 1 {
+				known = false
+				return false
+			}
+			exprs, ok := c.keyList(n.Results[0])
+			if !ok {
+				known = false
+				return false
+			}
+			for _, e := range exprs {
+				key := c.keyVar(e)
+				if key == nil {
+					known = false
+					return false
+				}
+				decls = append(decls, declaration{key: key, expr: e})
+			}
+		}
+		return true
+	})
+	return decls, known
+}
+
+// optionalDeclaredKeys returns the keys returned by the method name of an optional interface,
+// such as OptionalConsumes, and whether they could be determined statically. A type without
+// the method declares no keys, while a method promoted from an embedded type is not analyzed.
+func (c *checker) optionalDeclaredKeys(tn *types.TypeName, methods map[string]*ast.FuncDecl, name string) ([]declaration, bool) {
+	if fd, ok := methods[name]; ok {
+		return c.declaredKeys(fd)
+	}
+	if types.NewMethodSet(types.NewPointer(tn.Type())).Lookup(tn.Pkg(), name) != nil {
+		return nil, false
+	}
+	return nil, true
+}
+
+// keyList returns the elements of a DataKeys expression: nil, a call to modz.Keys or a
+// modz.DataKeys composite literal.
+func (c *checker) keyList(e ast.Expr) ([]ast.Expr, bool) {
+	switch e := ast.Unparen(e).(type) {
+	case *ast.Ident:
+		if _, ok := c.pass.TypesInfo.Uses[e].(*types.Nil); ok {
+			return nil, true
+		}
+	case *ast.CallExpr:
+		if fn, ok := c.pass.TypesInfo.Uses[calleeIdent(e.Fun)].(*types.Func); ok &&
+			fn.Pkg() == c.modz && fn.Name() == "Keys" && !e.Ellipsis.IsValid() {
+			return e.Args, true
+		}
+	case *ast.CompositeLit:
+		if named, ok := c.pass.TypesInfo.TypeOf(e).(*types.Named); ok &&
+			named.Obj().Pkg() == c.modz && named.Obj().Name() == "DataKeys" {
+			return e.Elts, true
+		}
+	}
+	return nil, false
+}
+
+// keyUses returns the calls to data key methods in the body of Configure, and whether they
+// are all the uses of keys made by Configure: false if the Binder escapes Configure.
+func (c *checker) keyUses(configure *ast.FuncDecl) ([]use, bool) {
+	binder := c.binderParam(configure)
+	accessorArgs := make(map[*ast.Ident]bool)
+	var uses []use
+	ast.Inspect(configure.Body, func(n ast.Node) bool {
+		call, ok := n.(*ast.CallExpr)
+		if !ok {
+			return true
+		}
+		sel, ok := ast.Unparen(call.Fun).(*ast.SelectorExpr)
+		if !ok {
+			return true
+		}
+		selection := c.pass.TypesInfo.Selections[sel]
+		if selection == nil || selection.Kind() != types.MethodVal || selection.Obj().Pkg() != c.modz {
+			return true
+		}
+		write, ok := keyMethods[sel.Sel.Name]
+		key := c.keyVar(sel.X)
+		if !ok || key == nil {
+			return true
+		}
+		uses = append(uses, use{key: key, write: write, call: call})
+		if len(call.Args) > 0 {
+			if id, ok := ast.Unparen(call.Args[0]).(*ast.Ident); ok {
+				accessorArgs[id] = true
+			}
+		}
+		return true
+	})
+	if binder == nil {
+		return uses, true
+	}
+	// The Binder escapes if it is used other than as the accessor of a key method or as the
+	// receiver of one of its own methods.
+	complete := true
+	ast.Inspect(configure.Body, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.SelectorExpr:
+			if id, ok := ast.Unparen(n.X).(*ast.Ident); ok && c.pass.TypesInfo.Uses[id] == binder {
+				return false
+			}
+		case *ast.Ident:
+			if c.pass.TypesInfo.Uses[n] == binder && !accessorArgs[n] {
+				complete = false
+			}
+		}
+		return complete
+	})
+	return uses, complete
+}
+
+// binderParam returns the Binder parameter of Configure, if it is named.
+func (c *checker) binderParam(configure *ast.FuncDecl) types.Object {
+	params := configure.Type.Params.List
+	if len(params) != 1 || len(params[0].Names) != 1 {
+		return nil
+	}
+	return c.pass.TypesInfo.Defs[params[0].Names[0]]
+}
+
+// keyVar returns the package-level data key variable denoted by e, or nil.
+func (c *checker) keyVar(e ast.Expr) *types.Var {
+	v, ok := c.pass.TypesInfo.Uses[calleeIdent(e)].(*types.Var)
+	if !ok || v.Pkg() == nil || v.Parent() != v.Pkg().Scope() {
+		return nil
+	}
+	if !types.Implements(v.Type(), c.dataKey) {
+		return nil
+	}
+	return v
+}
+
+// keyName returns the name of a key variable as written in the analyzed package.
+func (c *checker) keyName(v *types.Var) string {
+	if v.Pkg() == c.pass.Pkg {
+		return v.Name()
+	}
+	return v.Pkg().Name() + "." + v.Name()
+}
+
+// isKeyType reports whether v is a key of one of the named modz types, such as SetData.
+func (c *checker) isKeyType(v *types.Var, names ...string) bool {
+	named, ok := types.Unalias(v.Type()).(*types.Named)
+	if !ok {
+		return false
+	}
+	obj := named.Origin().Obj()
+	return obj.Pkg() == c.modz && slices.Contains(names, obj.Name())
+}
+
+// isModule reports whether the named type, or a pointer to it, implements modz.Module.
+func (c *checker) isModule(tn *types.TypeName) bool {
+	named, ok := tn.Type().(*types.Named)
+	if !ok || named.TypeParams().Len() > 0 {
+		return false
+	}
+	if _, ok := named.Underlying().(*types.Interface); ok {
+		return false
+	}
+	return types.Implements(named, c.module) || types.Implements(types.NewPointer(named), c.module)
+}
+
+// declares reports whether key is among decls.
+func declares(decls []declaration, key *types.Var) bool {
+	return slices.ContainsFunc(decls, func(d declaration) bool { return d.key == key })
+}
+
+// used reports whether uses holds a read, or a write, of key.
+func used(uses []use, key *types.Var, write bool) bool {
+	return slices.ContainsFunc(uses, func(u use) bool { return u.key == key && u.write == write })
+}
+
+// calleeIdent returns the identifier naming e, an identifier or a qualified identifier, or nil.
+func calleeIdent(e ast.Expr) *ast.Ident {
+	switch e := ast.Unparen(e).(type) {
+	case *ast.Ident:
+		return e
+	case *ast.SelectorExpr:
+		if _, ok := e.X.(*ast.Ident); ok {
+			return e.Sel
+		}
+	}
+	return nil
+}
+
+// receiverTypeName returns the named type of the receiver of a method declaration.
+func receiverTypeName(pass *analysis.Pass, fd *ast.FuncDecl) *types.TypeName {
+	fn, ok := pass.TypesInfo.Defs[fd.Name].(*types.Func)
+	if !ok {
+		return nil
+	}
+	recv := fn.Type().(*types.Signature).Recv()
+	if recv == nil {
+		return nil
+	}
+	t := recv.Type()
+	if ptr, ok := t.(*types.Pointer); ok {
+		t = ptr.Elem()
+	}
+	named, ok := types.Unalias(t).(*types.Named)
+	if !ok {
+		return nil
+	}
+	return named.Origin().Obj()
+}
+
+// findModz returns the modz package if pkg is, or imports, it.
+func findModz(pkg *types.Package) *types.Package {
+	if pkg.Path() == modzPath {
+		return pkg
+	}
+	for _, imp := range pkg.Imports() {
+		if imp.Path() == modzPath {
+			return imp
+		}
+	}
+	return nil
+}
+
+// lookupInterface returns the interface type named name in pkg, or nil.
+func lookupInterface(pkg *types.Package, name string) *types.Interface {
+	obj, ok := pkg.Scope().Lookup(name).(*types.TypeName)
+	if !ok {
+		return nil
+	}
+	iface, _ := obj.Type().Underlying().(*types.Interface)
+	return iface
+}
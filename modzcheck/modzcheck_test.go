@@ -0,0 +1,12 @@
+package modzcheck_test
+
+import (
+	"testing"
+
+	"github.com/goosz/modz/modzcheck"
+	"golang.org/x/tools/go/analysis/analysistest"
+)
+
+func TestAnalyzer(t *testing.T) {
+	analysistest.Run(t, analysistest.TestData(), modzcheck.Analyzer, "example")
+}
@@ -0,0 +1,164 @@
+package example
+
+import "github.com/goosz/modz"
+
+var (
+	Config  = modz.NewData[string]("config")
+	Server  = modz.NewData[int]("server")
+	Logger  = modz.NewData[string]("logger")
+	Metrics = modz.NewData[int]("metrics")
+	Routes  = modz.NewSetData[string]("routes")
+	Ready   = modz.NewMarker("ready")
+)
+
+// good declares exactly the keys it uses.
+type good struct{}
+
+func (*good) Name() string            { return "good" }
+func (*good) Produces() modz.DataKeys { return modz.Keys(Server, Routes) }
+func (*good) Consumes() modz.DataKeys { return modz.DataKeys{Config} }
+func (*good) Configure(b modz.Binder) error {
+	cfg, err := Config.Get(b)
+	if err != nil {
+		return err
+	}
+	return Server.Put(b, len(cfg))
+}
+
+// undeclared uses keys it does not declare.
+type undeclared struct{}
+
+func (undeclared) Name() string            { return "undeclared" }
+func (undeclared) Produces() modz.DataKeys { return nil }
+func (undeclared) Consumes() modz.DataKeys { return nil }
+func (undeclared) Configure(b modz.Binder) error {
+	if _, err := Config.Get(b); err != nil { // want `Configure of undeclared reads Config, which is not declared in Consumes\(\)`
+		return err
+	}
+	return Server.Provide(b, func() (int, error) { return 0, nil }) // want `Configure of undeclared writes Server, which is not declared in Produces\(\)`
+}
+
+// unused declares keys it does not use.
+type unused struct{}
+
+func (*unused) Name() string            { return "unused" }
+func (*unused) Produces() modz.DataKeys { return modz.Keys(Server, Metrics) } // want `Metrics is declared in Produces\(\) of unused but never written by Configure`
+func (*unused) Consumes() modz.DataKeys { return modz.Keys(Config, Logger) }  // want `Logger is declared in Consumes\(\) of unused but never read by Configure`
+func (*unused) Configure(b modz.Binder) error {
+	if _, err := Config.Get(b); err != nil {
+		return err
+	}
+	return Server.Put(b, 1)
+}
+
+// optional reads an optional key with Lookup.
+type optional struct{}
+
+func (*optional) Name() string                    { return "optional" }
+func (*optional) Produces() modz.DataKeys         { return nil }
+func (*optional) Consumes() modz.DataKeys         { return nil }
+func (*optional) OptionalConsumes() modz.DataKeys { return modz.Keys(Logger, Metrics) } // want `Metrics is declared in OptionalConsumes\(\) of optional but never read by Configure`
+func (*optional) Configure(b modz.Binder) error {
+	_, _, err := Logger.Lookup(b)
+	return err
+}
+
+// escaping passes its Binder to a helper, so unused declarations are not reported.
+type escaping struct{}
+
+func (*escaping) Name() string            { return "escaping" }
+func (*escaping) Produces() modz.DataKeys { return modz.Keys(Server) }
+func (*escaping) Consumes() modz.DataKeys { return modz.Keys(Config) }
+func (*escaping) Configure(b modz.Binder) error {
+	return configureServer(b)
+}
+
+func configureServer(b modz.Binder) error {
+	cfg, err := Config.Get(b)
+	if err != nil {
+		return err
+	}
+	return Server.Put(b, len(cfg))
+}
+
+// dynamic computes its declarations, so they are not checked.
+type dynamic struct {
+	produces modz.DataKeys
+}
+
+func (m *dynamic) Name() string            { return "dynamic" }
+func (m *dynamic) Produces() modz.DataKeys { return m.produces }
+func (m *dynamic) Consumes() modz.DataKeys { return nil }
+func (m *dynamic) Configure(b modz.Binder) error {
+	return Metrics.Put(b, 1)
+}
+
+// decorator reads and writes the key it decorates.
+type decorator struct{}
+
+func (*decorator) Name() string             { return "decorator" }
+func (*decorator) Produces() modz.DataKeys  { return nil }
+func (*decorator) Consumes() modz.DataKeys  { return modz.Keys(Config) }
+func (*decorator) Decorates() modz.DataKeys { return modz.Keys(Logger, Server) }
+func (*decorator) Configure(b modz.Binder) error {
+	prefix, err := Config.Get(b)
+	if err != nil {
+		return err
+	}
+	logger, err := Logger.Get(b)
+	if err != nil {
+		return err
+	}
+	if err := Logger.Put(b, prefix+logger); err != nil {
+		return err
+	}
+	return Metrics.Put(b, 1) // want `Configure of decorator writes Metrics, which is not declared in Produces\(\)`
+}
+
+// marking produces a marker, which has no value to write.
+type marking struct{}
+
+func (*marking) Name() string                  { return "marking" }
+func (*marking) Produces() modz.DataKeys       { return modz.Keys(Ready) }
+func (*marking) Consumes() modz.DataKeys       { return nil }
+func (*marking) Configure(b modz.Binder) error { return nil }
+
+// marked consumes a marker, which has no value to read.
+type marked struct{}
+
+func (*marked) Name() string                    { return "marked" }
+func (*marked) Produces() modz.DataKeys         { return nil }
+func (*marked) Consumes() modz.DataKeys         { return modz.Keys(Ready) }
+func (*marked) OptionalConsumes() modz.DataKeys { return nil }
+func (*marked) Configure(b modz.Binder) error   { return nil }
+
+// decorates declares the keys decorated by the modules embedding it.
+type decorates struct{}
+
+func (decorates) Decorates() modz.DataKeys { return modz.Keys(Logger) }
+
+// optionalKeys declares the keys optionally consumed by the modules embedding it.
+type optionalKeys struct{}
+
+func (optionalKeys) OptionalConsumes() modz.DataKeys { return modz.Keys(Metrics) }
+
+// embedding uses keys declared by methods promoted from the types it embeds, which are not
+// analyzed.
+type embedding struct {
+	decorates
+	optionalKeys
+}
+
+func (*embedding) Name() string            { return "embedding" }
+func (*embedding) Produces() modz.DataKeys { return nil }
+func (*embedding) Consumes() modz.DataKeys { return nil }
+func (*embedding) Configure(b modz.Binder) error {
+	logger, err := Logger.Get(b)
+	if err != nil {
+		return err
+	}
+	if _, _, err := Metrics.Lookup(b); err != nil {
+		return err
+	}
+	return Logger.Put(b, logger)
+}
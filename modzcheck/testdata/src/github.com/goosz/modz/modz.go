@@ -0,0 +1,50 @@
+// Package modz is a minimal stand-in for github.com/goosz/modz used by the analyzer tests.
+package modz
+
+type DataKey interface{ signature() string }
+
+type DataKeys []DataKey
+
+func Keys(keys ...DataKey) DataKeys { return keys }
+
+type DataReader interface{ getData(DataKey) (any, error) }
+
+type DataWriter interface{ putData(DataKey, any) error }
+
+type Data[T any] interface {
+	DataKey
+	Get(DataReader) (T, error)
+	Lookup(DataReader) (T, bool, error)
+	Put(DataWriter, T) error
+	Provide(DataWriter, func() (T, error)) error
+}
+
+type SetData[T any] interface {
+	DataKey
+	Get(DataReader) ([]T, error)
+	Add(DataWriter, T) error
+}
+
+type Marker interface {
+	DataKey
+	marker()
+}
+
+type Module interface {
+	Name() string
+	Produces() DataKeys
+	Consumes() DataKeys
+	Configure(Binder) error
+}
+
+type Binder interface {
+	DataReader
+	DataWriter
+	Install(Module) error
+}
+
+func NewData[T any](name string) Data[T] { return nil }
+
+func NewSetData[T any](name string) SetData[T] { return nil }
+
+func NewMarker(name string) Marker { return nil }
@@ -0,0 +1,115 @@
+package modzconfig
+
+import (
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// decodeRaw decodes a raw value into a T. A JSON value is decoded as JSON, except a JSON
+// string, which is decoded as text so that it can hold a duration or a TextUnmarshaler.
+func decodeRaw[T any](raw rawValue) (T, error) {
+	var val T
+	if raw.json {
+		var text string
+		if err := json.Unmarshal([]byte(raw.text), &text); err != nil {
+			err = json.Unmarshal([]byte(raw.text), &val)
+			return val, err
+		}
+		raw = rawValue{text: text}
+	}
+	err := decodeText(raw.text, reflect.ValueOf(&val).Elem())
+	return val, err
+}
+
+var (
+	durationType        = reflect.TypeFor[time.Duration]()
+	textUnmarshalerType = reflect.TypeFor[encoding.TextUnmarshaler]()
+)
+
+// decodeText decodes text into the settable value v.
+func decodeText(text string, v reflect.Value) error {
+	if v.CanAddr() && v.Addr().Type().Implements(textUnmarshalerType) {
+		return v.Addr().Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(text))
+	}
+	if v.Type() == durationType {
+		d, err := time.ParseDuration(text)
+		if err != nil {
+			return err
+		}
+		v.SetInt(int64(d))
+		return nil
+	}
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(text)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(text)
+		if err != nil {
+			return err
+		}
+		v.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(text, 0, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		n, err := strconv.ParseUint(text, 0, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(text, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetFloat(f)
+	case reflect.Slice:
+		if !isTextType(v.Type().Elem()) || strings.HasPrefix(text, "[") {
+			// A list of other types, or a JSON or TOML array.
+			return decodeJSON(text, v)
+		}
+		var items []string
+		if text != "" {
+			items = strings.Split(text, ",")
+		}
+		s := reflect.MakeSlice(v.Type(), len(items), len(items))
+		for i, item := range items {
+			if err := decodeText(strings.TrimSpace(item), s.Index(i)); err != nil {
+				return fmt.Errorf("item %d: %w", i, err)
+			}
+		}
+		v.Set(s)
+	default:
+		return decodeJSON(text, v)
+	}
+	return nil
+}
+
+// isTextType reports whether values of type t are decoded from text by decodeText, rather
+// than as JSON.
+func isTextType(t reflect.Type) bool {
+	if t == durationType || reflect.PointerTo(t).Implements(textUnmarshalerType) {
+		return true
+	}
+	switch t.Kind() {
+	case reflect.String, reflect.Bool,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64:
+		return true
+	}
+	return false
+}
+
+// decodeJSON decodes text as JSON into the settable value v.
+func decodeJSON(text string, v reflect.Value) error {
+	return json.Unmarshal([]byte(text), v.Addr().Interface())
+}
@@ -0,0 +1,60 @@
+package modzconfig
+
+import (
+	"net/netip"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func decodeTest[T any](t *testing.T, raw rawValue, want T) {
+	t.Helper()
+	got, err := decodeRaw[T](raw)
+	require.NoError(t, err)
+	require.Equal(t, want, got)
+}
+
+func TestDecodeRaw_Text(t *testing.T) {
+	decodeTest(t, rawValue{text: "hello"}, "hello")
+	decodeTest(t, rawValue{text: "true"}, true)
+	decodeTest(t, rawValue{text: "-42"}, -42)
+	decodeTest(t, rawValue{text: "0x10"}, int64(16))
+	decodeTest(t, rawValue{text: "255"}, uint8(255))
+	decodeTest(t, rawValue{text: "1.5"}, 1.5)
+	decodeTest(t, rawValue{text: "1m30s"}, 90*time.Second)
+	decodeTest(t, rawValue{text: "10.0.0.1"}, netip.MustParseAddr("10.0.0.1"))
+	decodeTest(t, rawValue{text: "a, b,c"}, []string{"a", "b", "c"})
+	decodeTest(t, rawValue{text: ""}, []string{})
+	decodeTest(t, rawValue{text: "1,2"}, []int{1, 2})
+	decodeTest(t, rawValue{text: "[1, 2]"}, []int{1, 2})
+	decodeTest(t, rawValue{text: `{"a": 1}`}, map[string]int{"a": 1})
+	decodeTest(t, rawValue{text: `{"Name": "x"}`}, struct{ Name string }{Name: "x"})
+}
+
+func TestDecodeRaw_JSON(t *testing.T) {
+	decodeTest(t, rawValue{text: `"hello"`, json: true}, "hello")
+	decodeTest(t, rawValue{text: `8080`, json: true}, 8080)
+	decodeTest(t, rawValue{text: `"8080"`, json: true}, 8080)
+	decodeTest(t, rawValue{text: `"5s"`, json: true}, 5*time.Second)
+	decodeTest(t, rawValue{text: `true`, json: true}, true)
+	decodeTest(t, rawValue{text: `["a", "b"]`, json: true}, []string{"a", "b"})
+	decodeTest(t, rawValue{text: `{"a": 1}`, json: true}, map[string]int{"a": 1})
+}
+
+func TestDecodeRaw_Errors(t *testing.T) {
+	_, err := decodeRaw[int](rawValue{text: "x"})
+	require.Error(t, err)
+	_, err = decodeRaw[uint8](rawValue{text: "256"})
+	require.Error(t, err)
+	_, err = decodeRaw[bool](rawValue{text: "maybe"})
+	require.Error(t, err)
+	_, err = decodeRaw[time.Duration](rawValue{text: "5 parsecs"})
+	require.Error(t, err)
+	_, err = decodeRaw[[]int](rawValue{text: "1,x"})
+	require.ErrorContains(t, err, "item 1")
+	_, err = decodeRaw[int](rawValue{text: `[1]`, json: true})
+	require.Error(t, err)
+	_, err = decodeRaw[netip.Addr](rawValue{text: "not an address"})
+	require.Error(t, err)
+}
@@ -0,0 +1,172 @@
+package modzconfig
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// parsedFile is a configuration file read by a reader. Its fields are parsed on the first
+// lookup of a field, so that a file read as a whole does not need to be in a known format.
+type parsedFile struct {
+	path string
+	// missing is true if the file does not exist.
+	missing bool
+	content []byte
+
+	parsed bool
+	// fields maps the names of the fields of the file to their raw values.
+	fields   map[string]rawValue
+	parseErr error
+}
+
+// readFile reads the configuration file at path. A missing file is not an error.
+func readFile(path string) (*parsedFile, error) {
+	content, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return &parsedFile{path: path, missing: true}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &parsedFile{path: path, content: content}, nil
+}
+
+// lookup returns the raw value of field, or the content of the file if field is empty.
+func (f *parsedFile) lookup(field string) (rawValue, bool, error) {
+	if f.missing {
+		return rawValue{}, false, nil
+	}
+	if field == "" {
+		return rawValue{text: strings.TrimSpace(string(f.content))}, true, nil
+	}
+	if !f.parsed {
+		f.parsed = true
+		if strings.EqualFold(filepath.Ext(f.path), ".json") {
+			f.fields, f.parseErr = parseJSON(f.content)
+		} else {
+			f.fields, f.parseErr = parseFlat(f.content)
+		}
+		if f.parseErr != nil {
+			f.parseErr = fmt.Errorf("parsing %s: %w", f.path, f.parseErr)
+		}
+	}
+	if f.parseErr != nil {
+		return rawValue{}, false, f.parseErr
+	}
+	raw, ok := f.fields[field]
+	return raw, ok, nil
+}
+
+// parseJSON flattens a JSON object into its fields, named by their dot-separated path. Both
+// objects and their leaves are fields, so that an object can be decoded as a whole.
+func parseJSON(content []byte) (map[string]rawValue, error) {
+	fields := make(map[string]rawValue)
+	var flatten func(prefix string, msg json.RawMessage) error
+	flatten = func(prefix string, msg json.RawMessage) error {
+		if prefix != "" {
+			fields[prefix] = rawValue{text: string(msg), json: true}
+		}
+		trimmed := bytes.TrimSpace(msg)
+		if len(trimmed) == 0 || trimmed[0] != '{' {
+			return nil
+		}
+		var obj map[string]json.RawMessage
+		if err := json.Unmarshal(msg, &obj); err != nil {
+			return err
+		}
+		for name, value := range obj {
+			if prefix != "" {
+				name = prefix + "." + name
+			}
+			if err := flatten(name, value); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	trimmed := bytes.TrimSpace(content)
+	if len(trimmed) == 0 || trimmed[0] != '{' {
+		return nil, fmt.Errorf("not a JSON object")
+	}
+	return fields, flatten("", content)
+}
+
+// parseFlat parses a flat key/value file: "key = value" or "key: value" lines, "[section]"
+// headers, comments starting with # or ;, and optionally quoted values.
+func parseFlat(content []byte) (map[string]rawValue, error) {
+	fields := make(map[string]rawValue)
+	section := ""
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	for lineNum := 1; scanner.Scan(); lineNum++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || line[0] == '#' || line[0] == ';' || line == "---" {
+			continue
+		}
+		if line[0] == '[' && line[len(line)-1] == ']' {
+			section = strings.TrimSpace(line[1 : len(line)-1])
+			continue
+		}
+		line = strings.TrimPrefix(line, "export ")
+		i := strings.IndexAny(line, "=:")
+		if i <= 0 {
+			return nil, fmt.Errorf("line %d: expected key = value or key: value", lineNum)
+		}
+		name := strings.TrimSpace(line[:i])
+		if section != "" {
+			name = section + "." + name
+		}
+		value, err := unquote(strings.TrimSpace(line[i+1:]))
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", lineNum, err)
+		}
+		fields[name] = rawValue{text: value}
+	}
+	return fields, scanner.Err()
+}
+
+// unquote removes the quotes around a double- or single-quoted value, and a trailing
+// comment from an unquoted one.
+func unquote(value string) (string, error) {
+	switch {
+	case value == "":
+		return "", nil
+	case value[0] == '"':
+		end := closingQuote(value)
+		if end < 0 {
+			return "", fmt.Errorf("unterminated quoted value")
+		}
+		return strconv.Unquote(value[:end+1])
+	case value[0] == '\'':
+		end := strings.IndexByte(value[1:], '\'')
+		if end < 0 {
+			return "", fmt.Errorf("unterminated quoted value")
+		}
+		return value[1 : end+1], nil
+	}
+	if i := strings.Index(value, " #"); i >= 0 {
+		value = strings.TrimSpace(value[:i])
+	}
+	return value, nil
+}
+
+// closingQuote returns the index of the double quote closing the value starting with a
+// double quote, or -1.
+func closingQuote(value string) int {
+	for i := 1; i < len(value); i++ {
+		switch value[i] {
+		case '\\':
+			i++
+		case '"':
+			return i
+		}
+	}
+	return -1
+}
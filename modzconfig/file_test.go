@@ -0,0 +1,79 @@
+package modzconfig
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseFlat(t *testing.T) {
+	fields, err := parseFlat([]byte(`
+# comment
+; comment
+---
+name = app
+export PORT=8080
+url: http://localhost:8080 # trailing comment
+quoted = "a \"b\" # c"
+single = 'x # y'
+empty =
+
+[database]
+dsn = "postgres://localhost/app"
+`))
+	require.NoError(t, err)
+	require.Equal(t, map[string]rawValue{
+		"name":         {text: "app"},
+		"PORT":         {text: "8080"},
+		"url":          {text: "http://localhost:8080"},
+		"quoted":       {text: `a "b" # c`},
+		"single":       {text: "x # y"},
+		"empty":        {text: ""},
+		"database.dsn": {text: "postgres://localhost/app"},
+	}, fields)
+}
+
+func TestParseFlat_Errors(t *testing.T) {
+	_, err := parseFlat([]byte("name = app\nnot a pair\n"))
+	require.ErrorContains(t, err, "line 2")
+	_, err = parseFlat([]byte(`name = "app`))
+	require.ErrorContains(t, err, "unterminated")
+	_, err = parseFlat([]byte(`name = 'app`))
+	require.ErrorContains(t, err, "unterminated")
+}
+
+func TestParseJSON(t *testing.T) {
+	fields, err := parseJSON([]byte(`{"a": {"b": 1, "c": "x"}, "d": [1]}`))
+	require.NoError(t, err)
+	require.Equal(t, map[string]rawValue{
+		"a":   {text: `{"b": 1, "c": "x"}`, json: true},
+		"a.b": {text: `1`, json: true},
+		"a.c": {text: `"x"`, json: true},
+		"d":   {text: `[1]`, json: true},
+	}, fields)
+
+	_, err = parseJSON([]byte(`[1]`))
+	require.ErrorContains(t, err, "not a JSON object")
+	_, err = parseJSON([]byte(`{"a": `))
+	require.Error(t, err)
+}
+
+func TestReadFile(t *testing.T) {
+	f, err := readFile(filepath.Join(t.TempDir(), "missing.json"))
+	require.NoError(t, err)
+	_, ok, err := f.lookup("a")
+	require.NoError(t, err)
+	require.False(t, ok)
+
+	// A file in an unknown format can still be read as a whole.
+	path := writeFile(t, "cert.pem", "-----BEGIN CERTIFICATE-----\n")
+	f, err = readFile(path)
+	require.NoError(t, err)
+	raw, ok, err := f.lookup("")
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.Equal(t, "-----BEGIN CERTIFICATE-----", raw.text)
+	_, _, err = f.lookup("a")
+	require.ErrorContains(t, err, "parsing "+path)
+}
@@ -0,0 +1,228 @@
+// Package modzconfig provides a [modz.Module] producing [modz.Data] values read from
+// environment variables and configuration files.
+//
+// A configuration module is created with [NewModule] from a list of entries, each mapping a
+// data key to where its value is read:
+//
+//	var Config = modzconfig.NewModule("config",
+//		modzconfig.Env(app.Port, "PORT"),
+//		modzconfig.File(app.Port, "/etc/app/config.json", "server.port"),
+//		modzconfig.File(app.DSN, "/etc/app/config.json", "database.dsn"),
+//		modzconfig.File(app.Password, "/run/secrets/db_password", ""),
+//	)
+//
+// The module produces every key of its entries. When several entries map the same key, the
+// first one holding a value wins, so that an environment variable can override a file.
+//
+// Values are decoded into the type T of their key: strings as is; booleans and numbers with
+// the strconv package; [time.Duration] with [time.ParseDuration]; types implementing
+// [encoding.TextUnmarshaler] with UnmarshalText; slices of these as comma-separated lists;
+// and any other type as JSON.
+//
+// The format of a file is chosen by its extension. A .json file holds a JSON object, whose
+// fields are named by their dot-separated path, such as "database.dsn". Any other file is
+// read in a flat key/value format, covering .env and .properties files and the flat subset
+// of YAML and TOML: one "key = value" or "key: value" pair per line, "[section]" headers
+// prefixing the keys that follow them with "section.", comments starting with # or ;, and
+// values optionally quoted. An empty field name reads the whole content of the file, with
+// surrounding white space trimmed, as for a secret mounted as a file.
+//
+// A key without a value, or whose value cannot be decoded or is rejected by a validator of
+// the key (see [modz.WithValidator]), is reported by Configure with an error naming the key
+// and the places it was read from; all the failing entries are reported together.
+package modzconfig
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/goosz/modz"
+)
+
+// Sentinel errors identifying the failures reported by a configuration [Module].
+var (
+	// ErrMissing reports a data key whose entries hold no value.
+	ErrMissing = errors.New("missing configuration value")
+	// ErrInvalid reports a value that cannot be decoded into the type of its data key, or
+	// that a validator of the key rejects.
+	ErrInvalid = errors.New("invalid configuration value")
+)
+
+// Entry maps a [modz.Data] key to the place its value is read from. Entries are created with
+// [Env] and [File].
+type Entry struct {
+	key modz.DataKey
+	// source describes where the value is read from, for error messages.
+	source string
+	// lookup returns the raw value of the entry, and false if it is not set.
+	lookup func(r *reader) (rawValue, bool, error)
+	// put decodes a raw value and stores it under the key.
+	put func(w modz.DataWriter, raw rawValue) error
+}
+
+// rawValue is a value read for an entry, before it is decoded.
+type rawValue struct {
+	text string
+	// json is true if text is a JSON value, read from a JSON file.
+	json bool
+}
+
+// Env returns an [Entry] reading the value of key from the environment variable name.
+// A variable set to the empty string holds a value.
+func Env[T any](key modz.Data[T], name string) Entry {
+	return newEntry(key, fmt.Sprintf("environment variable %s", name), func(*reader) (rawValue, bool, error) {
+		text, ok := os.LookupEnv(name)
+		return rawValue{text: text}, ok, nil
+	})
+}
+
+// File returns an [Entry] reading the value of key from the field of the configuration file
+// at path, or from the whole file if field is empty. A missing file holds no value.
+func File[T any](key modz.Data[T], path, field string) Entry {
+	source := fmt.Sprintf("file %s", path)
+	if field != "" {
+		source = fmt.Sprintf("field %s of file %s", field, path)
+	}
+	return newEntry(key, source, func(r *reader) (rawValue, bool, error) {
+		return r.lookupFile(path, field)
+	})
+}
+
+// newEntry returns an Entry decoding the values of key into T.
+func newEntry[T any](key modz.Data[T], source string, lookup func(r *reader) (rawValue, bool, error)) Entry {
+	if key == nil {
+		panic(fmt.Sprintf("modzconfig: data key for %s must not be nil", source))
+	}
+	return Entry{
+		key:    key,
+		source: source,
+		lookup: lookup,
+		put: func(w modz.DataWriter, raw rawValue) error {
+			val, err := decodeRaw[T](raw)
+			if err != nil {
+				return err
+			}
+			return key.Put(w, val)
+		},
+	}
+}
+
+// Module is a [modz.Module] producing the keys of its entries from environment variables and
+// configuration files. It is created with [NewModule].
+type Module struct {
+	name    string
+	entries []Entry
+	keys    modz.DataKeys
+}
+
+// Ensure that *Module implements modz.Module.
+var _ modz.Module = (*Module)(nil)
+
+// NewModule creates a configuration [Module] named name producing the keys of entries.
+func NewModule(name string, entries ...Entry) *Module {
+	m := &Module{name: name, entries: slices.Clone(entries)}
+	for _, e := range entries {
+		if !slices.Contains(m.keys, e.key) {
+			m.keys = append(m.keys, e.key)
+		}
+	}
+	return m
+}
+
+func (m *Module) Name() string {
+	return m.name
+}
+
+func (m *Module) Produces() modz.DataKeys {
+	return m.keys
+}
+
+func (m *Module) Consumes() modz.DataKeys {
+	return nil
+}
+
+// Configure reads the value of every key from the first of its entries holding one, and
+// stores it. It returns an error, joining the errors of all the failing keys, if any key
+// has no value or an invalid one; no value is stored then.
+func (m *Module) Configure(b modz.Binder) error {
+	r := newReader()
+	// Values are checked in a scratch store first, so that every invalid value is reported,
+	// and not only the first one the binder rejects.
+	scratch := modz.NewDataStore()
+	var errs []error
+	var values []found
+	for _, k := range m.keys {
+		f, err := m.lookupKey(scratch, r, k)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		values = append(values, f)
+	}
+	if len(errs) > 0 {
+		return errors.Join(errs...)
+	}
+	for _, f := range values {
+		if err := f.entry.put(b, f.raw); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// found is the value of a key read from one of its entries.
+type found struct {
+	entry Entry
+	raw   rawValue
+}
+
+// lookupKey reads the value of k from the first of its entries holding one, and checks it
+// by storing it in scratch.
+func (m *Module) lookupKey(scratch *modz.DataStore, r *reader, k modz.DataKey) (found, error) {
+	var sources []string
+	for _, e := range m.entries {
+		if e.key != k {
+			continue
+		}
+		sources = append(sources, e.source)
+		raw, ok, err := e.lookup(r)
+		if err != nil {
+			return found{}, fmt.Errorf("data key '%s': %s: %w", k, e.source, err)
+		}
+		if !ok {
+			continue
+		}
+		if err := e.put(scratch, raw); err != nil {
+			return found{}, fmt.Errorf("data key '%s': %s: %w: %w", k, e.source, ErrInvalid, err)
+		}
+		return found{entry: e, raw: raw}, nil
+	}
+	return found{}, fmt.Errorf("data key '%s': %w: not set in %s", k, ErrMissing, strings.Join(sources, ", "))
+}
+
+// reader reads configuration files, reading and parsing each file once.
+type reader struct {
+	files map[string]*parsedFile
+}
+
+func newReader() *reader {
+	return &reader{files: make(map[string]*parsedFile)}
+}
+
+// lookupFile returns the value of field in the file at path, or the content of the file if
+// field is empty.
+func (r *reader) lookupFile(path, field string) (rawValue, bool, error) {
+	f, ok := r.files[path]
+	if !ok {
+		var err error
+		f, err = readFile(path)
+		if err != nil {
+			return rawValue{}, false, err
+		}
+		r.files[path] = f
+	}
+	return f.lookup(field)
+}
@@ -0,0 +1,146 @@
+package modzconfig
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/goosz/modz"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	portKey    = modz.NewData[int]("port")
+	dsnKey     = modz.NewData[string]("dsn")
+	timeoutKey = modz.NewData[time.Duration]("timeout")
+	debugKey   = modz.NewData[bool]("debug")
+	hostsKey   = modz.NewData[[]string]("hosts")
+	secretKey  = modz.NewData[string]("secret")
+
+	errPrivilegedPort = errors.New("privileged port")
+	listenKey         = modz.NewData("listen", modz.WithValidator(func(port int) error {
+		if port < 1024 {
+			return errPrivilegedPort
+		}
+		return nil
+	}))
+)
+
+// writeFile writes content to a file named name in a temporary directory and returns its path.
+func writeFile(t *testing.T, name, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	return path
+}
+
+// build builds an assembly of m and returns it.
+func build(t *testing.T, m modz.Module) (modz.Assembly, error) {
+	t.Helper()
+	asm, err := modz.NewAssembly(m)
+	require.NoError(t, err)
+	return asm, asm.Build()
+}
+
+func TestModule(t *testing.T) {
+	jsonPath := writeFile(t, "config.json", `{
+		"server": {"port": 8080, "timeout": "5s", "hosts": ["a", "b"]},
+		"database": {"dsn": "postgres://localhost/app"}
+	}`)
+	secretPath := writeFile(t, "secret", "s3cr3t\n")
+	t.Setenv("APP_DEBUG", "true")
+
+	m := NewModule("config",
+		Env(portKey, "APP_PORT"),
+		File(portKey, jsonPath, "server.port"),
+		File(dsnKey, jsonPath, "database.dsn"),
+		File(timeoutKey, jsonPath, "server.timeout"),
+		File(hostsKey, jsonPath, "server.hosts"),
+		Env(debugKey, "APP_DEBUG"),
+		File(secretKey, secretPath, ""),
+	)
+	require.Equal(t, "config", m.Name())
+	require.Equal(t, modz.Keys(portKey, dsnKey, timeoutKey, hostsKey, debugKey, secretKey), m.Produces())
+	require.Empty(t, m.Consumes())
+
+	asm, err := build(t, m)
+	require.NoError(t, err)
+	port, err := portKey.Get(asm)
+	require.NoError(t, err)
+	require.Equal(t, 8080, port)
+	dsn, err := dsnKey.Get(asm)
+	require.NoError(t, err)
+	require.Equal(t, "postgres://localhost/app", dsn)
+	timeout, err := timeoutKey.Get(asm)
+	require.NoError(t, err)
+	require.Equal(t, 5*time.Second, timeout)
+	hosts, err := hostsKey.Get(asm)
+	require.NoError(t, err)
+	require.Equal(t, []string{"a", "b"}, hosts)
+	debug, err := debugKey.Get(asm)
+	require.NoError(t, err)
+	require.True(t, debug)
+	secret, err := secretKey.Get(asm)
+	require.NoError(t, err)
+	require.Equal(t, "s3cr3t", secret)
+}
+
+func TestModule_EnvOverridesFile(t *testing.T) {
+	path := writeFile(t, "config.env", "PORT=8080\n")
+	t.Setenv("APP_PORT", "9090")
+
+	asm, err := build(t, NewModule("config", Env(portKey, "APP_PORT"), File(portKey, path, "PORT")))
+	require.NoError(t, err)
+	port, err := portKey.Get(asm)
+	require.NoError(t, err)
+	require.Equal(t, 9090, port)
+}
+
+func TestModule_Missing(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "missing.yaml")
+
+	_, err := build(t, NewModule("config",
+		Env(portKey, "MODZCONFIG_TEST_UNSET"),
+		File(portKey, path, "port"),
+	))
+	require.ErrorIs(t, err, ErrMissing)
+	require.ErrorContains(t, err, fmt.Sprint(portKey))
+	require.ErrorContains(t, err, "not set in environment variable MODZCONFIG_TEST_UNSET, field port of file "+path)
+	var configErr *modz.ConfigurationError
+	require.ErrorAs(t, err, &configErr)
+	require.Equal(t, "github.com/goosz/modz/modzconfig:config", configErr.ModuleID)
+}
+
+func TestModule_Invalid(t *testing.T) {
+	t.Setenv("APP_PORT", "eighty")
+	t.Setenv("APP_LISTEN", "80")
+	t.Setenv("APP_DEBUG", "yes please")
+
+	_, err := build(t, NewModule("config",
+		Env(portKey, "APP_PORT"),
+		Env(listenKey, "APP_LISTEN"),
+		Env(debugKey, "APP_DEBUG"),
+		Env(dsnKey, "MODZCONFIG_TEST_UNSET"),
+	))
+	require.ErrorIs(t, err, ErrInvalid)
+	require.ErrorIs(t, err, ErrMissing)
+	require.ErrorIs(t, err, modz.ErrInvalidValue)
+	require.ErrorIs(t, err, errPrivilegedPort)
+	require.ErrorContains(t, err, "environment variable APP_PORT")
+	require.ErrorContains(t, err, "environment variable APP_DEBUG")
+	require.ErrorContains(t, err, "environment variable APP_LISTEN")
+}
+
+func TestModule_ParseError(t *testing.T) {
+	path := writeFile(t, "config.json", `[1, 2]`)
+
+	_, err := build(t, NewModule("config", File(portKey, path, "port")))
+	require.ErrorContains(t, err, "parsing "+path)
+}
+
+func TestNewModule_NilKey(t *testing.T) {
+	require.Panics(t, func() { Env[int](nil, "PORT") })
+}
@@ -0,0 +1,41 @@
+package modztest
+
+import (
+	"context"
+	"fmt"
+	"testing"
+)
+
+type ctxKey struct{}
+
+func withValue(ctx context.Context) context.Context {
+	return context.WithValue(ctx, ctxKey{}, "value")
+}
+
+// recordingTB is a testing.TB recording a fatal failure instead of stopping the test.
+type recordingTB struct {
+	testing.TB
+	failed bool
+	msg    string
+}
+
+// errFatal is panicked by recordingTB.Fatalf to stop the function under test.
+var errFatal = fmt.Errorf("fatal")
+
+func (tb *recordingTB) Helper() {}
+
+func (tb *recordingTB) Fatalf(format string, args ...any) {
+	tb.failed = true
+	tb.msg = fmt.Sprintf(format, args...)
+	panic(errFatal)
+}
+
+// runAndRecover runs f, recovering from the panic of a recordingTB's Fatalf.
+func runAndRecover(tb *recordingTB, f func()) {
+	defer func() {
+		if r := recover(); r != nil && r != errFatal {
+			panic(r)
+		}
+	}()
+	f()
+}
@@ -0,0 +1,204 @@
+// Package modztest provides utilities for testing a single [modz.Module] in isolation.
+//
+// [Configure] runs the Configure method of a module as an [modz.Assembly] would, without the
+// modules producing the data it consumes: the values of its consumed keys are seeded with
+// [modz.WithData] instead. The modules it installs are recorded rather than installed, so
+// that they are neither configured nor need their own dependencies. The returned [Result]
+// lists the keys the module produced and reads their values:
+//
+//	res, err := modztest.Configure(&ServerModule{},
+//		modz.WithData(ConfigKey, Config{Port: 8080}),
+//	)
+//	require.NoError(t, err)
+//	addr, err := AddrKey.Get(res)
+//	require.NoError(t, err)
+//	require.Equal(t, ":8080", addr)
+//	require.Equal(t, []string{modz.ModuleID(&HandlersModule{})}, res.InstalledIDs())
+//
+// A consumed key that is not seeded is reported as a [modz.MissingProducerError]; an
+// optionally consumed key that is not seeded is absent. [modz.SetData] and [modz.MapData] keys
+// cannot be seeded: the module reads the elements contributed by no module, an empty collection.
+// Installed modules are recorded as passed, so installing a module twice is not reported.
+//
+// A [modz.Decorator] decorates the value of a key held by a parent assembly, passed with
+// [modz.WithParent]: a key seeded with [modz.WithData] is final and cannot be decorated. The
+// modules named by a [modz.Requirer] are not required, since they are not installed.
+//
+// The module is configured through a host module defined by this package, which carries its
+// Name() and declarations. Errors and [modz.DataEntry] values name the module by the ID of the
+// host, with the package path of modztest.
+package modztest
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"slices"
+	"sync"
+	"testing"
+
+	"github.com/goosz/modz"
+)
+
+// Result is the outcome of configuring a module with [Configure].
+//
+// Result implements [modz.DataReader]: the values of the keys the module produced, and of the
+// seeded keys, are read with their [modz.Data] key as from a built [modz.Assembly]. Reading
+// fails if the configuration failed.
+type Result struct {
+	modz.DataReader
+
+	// Installed lists the modules passed to [modz.Binder].Install, in order.
+	Installed []modz.Module
+
+	// Produced lists the keys the module produced, sorted by signature, excluding the seeded
+	// ones. A [modz.SetData] or [modz.MapData] key declared in Produces() is listed even if the
+	// module added nothing to it: its value is then an empty collection.
+	Produced []modz.DataKey
+}
+
+// InstalledIDs returns the IDs, as returned by [modz.ModuleID], of the modules the module
+// installed, in order.
+func (r *Result) InstalledIDs() []string {
+	ids := make([]string, len(r.Installed))
+	for i, m := range r.Installed {
+		ids[i] = modz.ModuleID(m)
+	}
+	return ids
+}
+
+// HasProduced reports whether the module produced key.
+func (r *Result) HasProduced(key modz.DataKey) bool {
+	return slices.Contains(r.Produced, key)
+}
+
+// Configure configures m in isolation and returns what it produced and installed.
+//
+// The options are passed to [modz.NewAssemblyWithOptions]; they typically seed the keys m
+// consumes with [modz.WithData], and may bound the configuration with
+// [modz.WithModuleTimeout].
+//
+// Configure returns an error if the assembly cannot be created, in which case the Result is
+// nil, or built, including when m returns an error or panics. If the build fails, the Result
+// is returned with the modules m installed and the keys it produced before failing.
+func Configure(m modz.Module, opts ...modz.AssemblyOption) (*Result, error) {
+	return ConfigureContext(context.Background(), m, opts...)
+}
+
+// ConfigureContext is like [Configure] but configures m under the given context, which m
+// observes through [modz.Binder].Context().
+func ConfigureContext(ctx context.Context, m modz.Module, opts ...modz.AssemblyOption) (*Result, error) {
+	if m == nil {
+		return nil, fmt.Errorf("modztest: module must not be nil")
+	}
+	h := &host{module: m}
+	asm, err := modz.NewAssemblyWithOptions(opts, h)
+	if err != nil {
+		return nil, err
+	}
+	res := &Result{DataReader: asm}
+	err = asm.BuildContext(ctx)
+
+	res.Installed = h.installedModules()
+	hostID := modz.ModuleID(h)
+	for _, entry := range asm.DataEntries() {
+		if slices.Contains(entry.Producers, hostID) && !entry.Seeded {
+			res.Produced = append(res.Produced, entry.Key)
+		}
+	}
+	return res, err
+}
+
+// Run is like [ConfigureContext] under the context of tb, but fails the test if configuring
+// m fails.
+func Run(tb testing.TB, m modz.Module, opts ...modz.AssemblyOption) *Result {
+	tb.Helper()
+	res, err := ConfigureContext(tb.Context(), m, opts...)
+	if err != nil {
+		tb.Fatalf("modztest: configuring module '%s': %v", modz.ModuleID(m), err)
+	}
+	return res
+}
+
+// RequireValue fails the test unless the value of key read from r equals want, as compared
+// by [reflect.DeepEqual].
+func RequireValue[T any](tb testing.TB, r modz.DataReader, key modz.Data[T], want T) {
+	tb.Helper()
+	got, err := key.Get(r)
+	if err != nil {
+		tb.Fatalf("modztest: reading '%s': %v", key, err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		tb.Fatalf("modztest: value of '%s' is %#v, want %#v", key, got, want)
+	}
+}
+
+// host is the module configured by the assembly in place of the module under test. It
+// declares the same keys, including the decorated ones, and configures the module with a
+// binder recording its installs.
+type host struct {
+	module modz.Module
+
+	mu          sync.Mutex
+	configuring bool
+	installed   []modz.Module
+}
+
+func (h *host) Name() string            { return h.module.Name() }
+func (h *host) Produces() modz.DataKeys { return h.module.Produces() }
+func (h *host) Consumes() modz.DataKeys { return h.module.Consumes() }
+
+func (h *host) OptionalConsumes() modz.DataKeys {
+	if oc, ok := h.module.(modz.OptionalConsumer); ok {
+		return oc.OptionalConsumes()
+	}
+	return nil
+}
+
+func (h *host) Decorates() modz.DataKeys {
+	if d, ok := h.module.(modz.Decorator); ok {
+		return d.Decorates()
+	}
+	return nil
+}
+
+func (h *host) Configure(b modz.Binder) error {
+	h.setConfiguring(true)
+	defer h.setConfiguring(false)
+	return h.module.Configure(&recordingBinder{Binder: b, host: h})
+}
+
+// setConfiguring records whether the module's Configure method is running.
+func (h *host) setConfiguring(configuring bool) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.configuring = configuring
+}
+
+// installedModules returns the modules installed so far.
+func (h *host) installedModules() []modz.Module {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return slices.Clone(h.installed)
+}
+
+// recordingBinder is the [modz.Binder] passed to the module under test. Data access goes to
+// the assembly's binder, while installed modules are recorded instead of being installed.
+type recordingBinder struct {
+	modz.Binder
+	host *host
+}
+
+func (b *recordingBinder) Install(m modz.Module) error {
+	if m == nil {
+		// Let the assembly report the invalid argument as it would without modztest.
+		return b.Binder.Install(m)
+	}
+	b.host.mu.Lock()
+	defer b.host.mu.Unlock()
+	if !b.host.configuring {
+		return fmt.Errorf("Install: can only be called during configuration phase: %w", modz.ErrPhase)
+	}
+	b.host.installed = append(b.host.installed, m)
+	return nil
+}
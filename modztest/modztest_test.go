@@ -0,0 +1,307 @@
+package modztest
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/goosz/modz"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	portKey    = modz.NewData[int]("port")
+	addrKey    = modz.NewData[string]("addr")
+	debugKey   = modz.NewData[bool]("debug")
+	handlerKey = modz.NewSetData[string]("handlers")
+)
+
+// testModule is a configurable module for testing the harness.
+type testModule struct {
+	name             string
+	produces         modz.DataKeys
+	consumes         modz.DataKeys
+	optionalConsumes modz.DataKeys
+	configureFunc    func(modz.Binder) error
+}
+
+func (m *testModule) Name() string                    { return m.name }
+func (m *testModule) Produces() modz.DataKeys         { return m.produces }
+func (m *testModule) Consumes() modz.DataKeys         { return m.consumes }
+func (m *testModule) OptionalConsumes() modz.DataKeys { return m.optionalConsumes }
+func (m *testModule) Configure(b modz.Binder) error {
+	if m.configureFunc != nil {
+		return m.configureFunc(b)
+	}
+	return nil
+}
+
+func newServerModule() *testModule {
+	return &testModule{
+		name:             "server",
+		produces:         modz.Keys(addrKey, handlerKey),
+		consumes:         modz.Keys(portKey),
+		optionalConsumes: modz.Keys(debugKey),
+		configureFunc: func(b modz.Binder) error {
+			port, err := portKey.Get(b)
+			if err != nil {
+				return err
+			}
+			if err := b.Install(&testModule{name: "handlers", consumes: modz.Keys(addrKey)}); err != nil {
+				return err
+			}
+			return addrKey.Put(b, fmt.Sprintf(":%d", port))
+		},
+	}
+}
+
+func TestConfigure(t *testing.T) {
+	res, err := Configure(newServerModule(), modz.WithData(portKey, 8))
+	require.NoError(t, err)
+
+	addr, err := addrKey.Get(res)
+	require.NoError(t, err)
+	require.Equal(t, ":8", addr)
+	port, err := portKey.Get(res)
+	require.NoError(t, err)
+	require.Equal(t, 8, port)
+
+	require.Equal(t, []modz.DataKey{addrKey, handlerKey}, res.Produced)
+	require.True(t, res.HasProduced(addrKey))
+	require.False(t, res.HasProduced(portKey))
+	handlers, err := handlerKey.Get(res)
+	require.NoError(t, err)
+	require.Empty(t, handlers)
+
+	require.Len(t, res.Installed, 1)
+	require.Equal(t, []string{"github.com/goosz/modz/modztest:handlers"}, res.InstalledIDs())
+}
+
+func TestConfigure_Collection(t *testing.T) {
+	m := &testModule{
+		name:     "routes",
+		produces: modz.Keys(handlerKey),
+		configureFunc: func(b modz.Binder) error {
+			if err := handlerKey.Add(b, "/health"); err != nil {
+				return err
+			}
+			return handlerKey.Add(b, "/metrics")
+		},
+	}
+	res, err := Configure(m)
+	require.NoError(t, err)
+
+	require.Equal(t, []modz.DataKey{handlerKey}, res.Produced)
+	handlers, err := handlerKey.Get(res)
+	require.NoError(t, err)
+	require.Equal(t, []string{"/health", "/metrics"}, handlers)
+}
+
+func TestConfigure_OptionalConsumes(t *testing.T) {
+	var debug, ok bool
+	m := &testModule{
+		name:             "logger",
+		optionalConsumes: modz.Keys(debugKey),
+		configureFunc: func(b modz.Binder) error {
+			var err error
+			debug, ok, err = debugKey.Lookup(b)
+			return err
+		},
+	}
+
+	_, err := Configure(m)
+	require.NoError(t, err)
+	require.False(t, ok)
+
+	_, err = Configure(m, modz.WithData(debugKey, true))
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.True(t, debug)
+}
+
+// decoratorModule is a testModule that also implements modz.Decorator.
+type decoratorModule struct {
+	testModule
+	decorates modz.DataKeys
+}
+
+func (m *decoratorModule) Decorates() modz.DataKeys { return m.decorates }
+
+func TestConfigure_Decorator(t *testing.T) {
+	parent, err := modz.NewAssembly(&testModule{
+		name:          "addr",
+		produces:      modz.Keys(addrKey),
+		configureFunc: func(b modz.Binder) error { return addrKey.Put(b, ":80") },
+	})
+	require.NoError(t, err)
+	require.NoError(t, parent.Build())
+
+	decorator := &decoratorModule{
+		testModule: testModule{
+			name: "decorator",
+			configureFunc: func(b modz.Binder) error {
+				addr, err := addrKey.Get(b)
+				if err != nil {
+					return err
+				}
+				return addrKey.Put(b, "localhost"+addr)
+			},
+		},
+		decorates: modz.Keys(addrKey),
+	}
+	res := Run(t, decorator, modz.WithParent(parent))
+	RequireValue(t, res, addrKey, "localhost:80")
+	require.True(t, res.HasProduced(addrKey))
+
+	// A seeded value is final.
+	_, err = Configure(decorator, modz.WithData(addrKey, ":80"))
+	require.ErrorIs(t, err, modz.ErrAlreadySet)
+}
+
+// requirerModule is a testModule that also implements modz.Requirer.
+type requirerModule struct {
+	testModule
+}
+
+func (m *requirerModule) Requires() []modz.Module {
+	return []modz.Module{&testModule{name: "migrations"}}
+}
+
+func TestConfigure_Requirer(t *testing.T) {
+	// The required module is not installed, and not waited for.
+	res := Run(t, &requirerModule{testModule: testModule{name: "seed"}})
+	require.Empty(t, res.Installed)
+}
+
+func TestConfigure_MissingSeed(t *testing.T) {
+	res, err := Configure(newServerModule())
+	require.ErrorIs(t, err, modz.ErrMissingProducer)
+	require.NotNil(t, res)
+	require.Empty(t, res.Produced)
+	require.Empty(t, res.Installed)
+
+	_, err = addrKey.Get(res)
+	require.ErrorIs(t, err, modz.ErrNotBuilt)
+}
+
+func TestConfigure_ModuleError(t *testing.T) {
+	errBoom := errors.New("boom")
+	m := &testModule{
+		name:     "failing",
+		produces: modz.Keys(addrKey),
+		configureFunc: func(b modz.Binder) error {
+			if err := b.Install(&testModule{name: "child"}); err != nil {
+				return err
+			}
+			return errBoom
+		},
+	}
+	res, err := Configure(m)
+	require.ErrorIs(t, err, errBoom)
+	var configErr *modz.ConfigurationError
+	require.ErrorAs(t, err, &configErr)
+	require.Equal(t, "github.com/goosz/modz/modztest:failing", configErr.ModuleID)
+	require.Equal(t, []string{"github.com/goosz/modz/modztest:child"}, res.InstalledIDs())
+}
+
+func TestConfigure_Panic(t *testing.T) {
+	m := &testModule{
+		name: "panicking",
+		configureFunc: func(modz.Binder) error {
+			panic("boom")
+		},
+	}
+	_, err := Configure(m)
+	require.ErrorIs(t, err, modz.ErrPanic)
+}
+
+func TestConfigure_UndeclaredKey(t *testing.T) {
+	m := &testModule{
+		name: "undeclared",
+		configureFunc: func(b modz.Binder) error {
+			return addrKey.Put(b, "x")
+		},
+	}
+	_, err := Configure(m)
+	require.ErrorIs(t, err, modz.ErrUndeclaredKey)
+}
+
+func TestConfigure_InstallNil(t *testing.T) {
+	m := &testModule{
+		name: "nil-installer",
+		configureFunc: func(b modz.Binder) error {
+			return b.Install(nil)
+		},
+	}
+	res, err := Configure(m)
+	require.ErrorIs(t, err, modz.ErrInvalidArgument)
+	require.Empty(t, res.Installed)
+}
+
+func TestConfigure_InstallOutsideConfigure(t *testing.T) {
+	var retained modz.Binder
+	m := &testModule{
+		name: "retaining",
+		configureFunc: func(b modz.Binder) error {
+			retained = b
+			return nil
+		},
+	}
+	res, err := Configure(m)
+	require.NoError(t, err)
+
+	err = retained.Install(&testModule{name: "late"})
+	require.ErrorIs(t, err, modz.ErrPhase)
+	require.Empty(t, res.Installed)
+}
+
+func TestConfigure_InvalidArguments(t *testing.T) {
+	res, err := Configure(nil)
+	require.Error(t, err)
+	require.Nil(t, res)
+
+	res, err = Configure(newServerModule(), modz.WithParallelism(0))
+	require.Error(t, err)
+	require.Nil(t, res)
+}
+
+func TestConfigureContext(t *testing.T) {
+	var value any
+	m := &testModule{
+		name: "context",
+		configureFunc: func(b modz.Binder) error {
+			value = b.Context().Value(ctxKey{})
+			return nil
+		},
+	}
+	_, err := ConfigureContext(withValue(t.Context()), m)
+	require.NoError(t, err)
+	require.Equal(t, "value", value)
+}
+
+func TestRun(t *testing.T) {
+	res := Run(t, newServerModule(), modz.WithData(portKey, 8))
+	RequireValue(t, res, addrKey, ":8")
+	RequireValue(t, res, portKey, 8)
+}
+
+func TestRun_Failure(t *testing.T) {
+	rec := &recordingTB{TB: t}
+	runAndRecover(rec, func() { Run(rec, newServerModule()) })
+	require.True(t, rec.failed)
+	require.Contains(t, rec.msg, "configuring module 'github.com/goosz/modz/modztest:server'")
+}
+
+func TestRequireValue_Mismatch(t *testing.T) {
+	res := Run(t, newServerModule(), modz.WithData(portKey, 8))
+
+	rec := &recordingTB{TB: t}
+	runAndRecover(rec, func() { RequireValue(rec, res, addrKey, ":9") })
+	require.True(t, rec.failed)
+	require.Contains(t, rec.msg, `is ":8", want ":9"`)
+
+	rec = &recordingTB{TB: t}
+	runAndRecover(rec, func() { RequireValue(rec, res, debugKey, true) })
+	require.True(t, rec.failed)
+	require.Contains(t, rec.msg, "reading")
+}
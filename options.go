@@ -0,0 +1,114 @@
+package modz
+
+import (
+	"fmt"
+	"log/slog"
+	"time"
+)
+
+// AssemblyOption configures optional behavior of an [Assembly].
+//
+// Options are passed to [NewAssemblyWithOptions] and are applied in order before any
+// module is installed. An option returns an error if its arguments are invalid.
+type AssemblyOption func(*assemblyOptions) error
+
+// assemblyOptions holds the settings configured by AssemblyOption values.
+type assemblyOptions struct {
+	// parallelism is the maximum number of modules configured concurrently by Build.
+	parallelism int
+	// buildTimeout bounds the whole Build, if positive.
+	buildTimeout time.Duration
+	// moduleTimeout bounds the configuration of each module, if positive.
+	moduleTimeout time.Duration
+	// aggregateErrors makes construction and Build collect all errors instead of stopping at the first.
+	aggregateErrors bool
+	// moduleOverrides maps the IDs of overridden modules to their replacements.
+	moduleOverrides map[string]Module
+	// seededData holds the values set with WithData before any module is installed.
+	seededData map[DataKey]any
+	// parent is the built assembly whose data a child assembly inherits, if any.
+	parent *assembly
+	// observer is notified of the progress of Build, if set.
+	observer BuildObserver
+	// logger receives the events of the assembly; it discards them unless set with WithLogger.
+	logger *slog.Logger
+}
+
+// defaultAssemblyOptions returns the settings used when no options are given.
+func defaultAssemblyOptions() assemblyOptions {
+	return assemblyOptions{
+		parallelism:     1,
+		moduleOverrides: make(map[string]Module),
+		seededData:      make(map[DataKey]any),
+		logger:          slog.New(slog.DiscardHandler),
+	}
+}
+
+// WithParallelism enables parallel configuration of independent modules.
+//
+// By default, Build configures one module at a time. With a parallelism greater than one,
+// Build configures up to that many ready modules concurrently: every module whose consumed
+// [Data] is available is handed to a worker as soon as one is free, and modules become ready
+// as soon as the values they consume are Put, even while their producers are still running.
+// This speeds up assemblies whose modules spend time on I/O during configuration.
+//
+// Modules configured in parallel mode must not rely on being configured in any particular
+// order beyond what their declared [Data] dependencies guarantee.
+//
+// Returns an error from [NewAssemblyWithOptions] if workers is less than one.
+func WithParallelism(workers int) AssemblyOption {
+	return func(o *assemblyOptions) error {
+		if workers < 1 {
+			return fmt.Errorf("WithParallelism: workers must be at least 1, got %d", workers)
+		}
+		o.parallelism = workers
+		return nil
+	}
+}
+
+// WithBuildTimeout bounds the total time Build may take.
+//
+// The deadline applies to the context passed to BuildContext() (or to a background context
+// for Build()). When it is reached, Build stops starting new modules and returns a
+// [ConfigurationError] for the module that was still configuring.
+//
+// Returns an error from [NewAssemblyWithOptions] if d is not positive.
+func WithBuildTimeout(d time.Duration) AssemblyOption {
+	return func(o *assemblyOptions) error {
+		if d <= 0 {
+			return fmt.Errorf("WithBuildTimeout: timeout must be positive, got %v", d)
+		}
+		o.buildTimeout = d
+		return nil
+	}
+}
+
+// WithModuleTimeout bounds the time each module's Configure() may take.
+//
+// Each module's [Binder] context gets its own deadline. When a module exceeds it, Build
+// returns a [ConfigurationError] for that module without waiting for Configure() to return.
+//
+// Returns an error from [NewAssemblyWithOptions] if d is not positive.
+func WithModuleTimeout(d time.Duration) AssemblyOption {
+	return func(o *assemblyOptions) error {
+		if d <= 0 {
+			return fmt.Errorf("WithModuleTimeout: timeout must be positive, got %v", d)
+		}
+		o.moduleTimeout = d
+		return nil
+	}
+}
+
+// WithAggregateErrors makes the [Assembly] report every error instead of stopping at the first.
+//
+// [NewAssemblyWithOptions] installs every module and returns all installation errors together.
+// Build keeps configuring every module whose dependencies can still be satisfied after a module
+// fails; modules that consume [Data] a failed module did not produce are skipped. Build then
+// returns an [AssemblyError] holding every [ConfigurationError], every dependency error
+// ([CycleError], [MissingProducerError]) and the modules skipped because of upstream failures.
+func WithAggregateErrors() AssemblyOption {
+	return func(o *assemblyOptions) error {
+		o.aggregateErrors = true
+		return nil
+	}
+}
@@ -0,0 +1,109 @@
+package modz
+
+import (
+	"fmt"
+)
+
+// WithModuleOverride replaces a module with another one when it is installed.
+//
+// Whenever a module whose ID is moduleID is installed, whether it is passed to
+// [NewAssemblyWithOptions] or installed by another module during Build, replacement is
+// installed in its place. This lets tests swap a real module, such as one connecting to a
+// database, for a fake without editing the module tree. The ID of a module is returned by
+// [ModuleID] and is the one reported in errors and in the [Graph].
+//
+// The replacement must be compatible with the module it replaces: it must declare in
+// Produces() every [DataKey] the original module declares, so that the modules consuming
+// them can still be configured. Installing an incompatible replacement fails with
+// [ErrIncompatibleOverride]. The replacement may consume different keys than the original.
+//
+// The replaced module is still considered installed under its own ID: installing it again
+// is an error unless it is a [Singleton]. An override for a module that is never installed
+// has no effect.
+//
+// Returns an error from [NewAssemblyWithOptions] if moduleID is empty, if replacement is
+// nil, or if moduleID is already overridden.
+func WithModuleOverride(moduleID string, replacement Module) AssemblyOption {
+	return func(o *assemblyOptions) error {
+		if moduleID == "" {
+			return fmt.Errorf("WithModuleOverride: module ID must not be empty")
+		}
+		if replacement == nil {
+			return fmt.Errorf("WithModuleOverride: replacement for '%s' must not be nil", moduleID)
+		}
+		if _, exists := o.moduleOverrides[moduleID]; exists {
+			return fmt.Errorf("WithModuleOverride: module '%s' is already overridden", moduleID)
+		}
+		o.moduleOverrides[moduleID] = replacement
+		return nil
+	}
+}
+
+// WithData seeds the value of a [Data] key before any module is installed.
+//
+// The value is available to consumers from the start of Build, whether or not a module
+// produces the key. A module whose Produces() keys are all seeded is skipped: it is neither
+// configured nor does it install other modules. A module producing some seeded keys and some
+// other keys is configured as usual, but the values it Puts under seeded keys are ignored.
+//
+// Returns an error from [NewAssemblyWithOptions] if key is nil or is already seeded, or if
+// value is rejected by a validator of key (see [WithValidator]).
+func WithData[T any](key Data[T], value T) AssemblyOption {
+	return func(o *assemblyOptions) error {
+		if key == nil {
+			return fmt.Errorf("WithData: key must not be nil")
+		}
+		if _, exists := o.seededData[key]; exists {
+			return fmt.Errorf("WithData: data key '%s' is already seeded", key)
+		}
+		if vk, ok := key.(validatedKey); ok {
+			if err := vk.validate(value); err != nil {
+				return fmt.Errorf("WithData: %w", err)
+			}
+		}
+		o.seededData[key] = value
+		return nil
+	}
+}
+
+// ModuleID returns the ID of a module: the signature, combining its package path and Name(),
+// under which the [Assembly] tracks it and reports errors about it.
+func ModuleID(m Module) string {
+	return newModuleSignature(m).String()
+}
+
+// overrideModule returns the module to install in place of the module with the given
+// signature: its replacement if it is overridden, or m itself.
+//
+// Returns an error if the replacement does not produce every key m produces.
+func (a *assembly) overrideModule(m Module, sig moduleSignature) (Module, error) {
+	replacement, ok := a.options.moduleOverrides[sig.String()]
+	if !ok {
+		return m, nil
+	}
+	produces := make(map[DataKey]struct{})
+	for _, k := range replacement.Produces() {
+		produces[k] = struct{}{}
+	}
+	for _, k := range m.Produces() {
+		if _, ok := produces[k]; !ok {
+			return nil, newInstallError(ErrIncompatibleOverride, sig.String(),
+				fmt.Sprintf("replacement '%s' does not produce '%s'", ModuleID(replacement), k))
+		}
+	}
+	return replacement, nil
+}
+
+// isSeeded reports whether every key b produces has a value seeded with [WithData], in which
+// case the module does not need to be configured.
+func (a *assembly) isSeeded(b *binder) bool {
+	if len(b.produces) == 0 {
+		return false
+	}
+	for k := range b.produces {
+		if _, ok := a.options.seededData[k]; !ok {
+			return false
+		}
+	}
+	return true
+}
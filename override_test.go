@@ -0,0 +1,157 @@
+package modz
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithModuleOverride(t *testing.T) {
+	db := &MockModule{
+		NameValue:     "db",
+		ProducesValue: Keys(FooKey),
+		ConfigureFunc: func(b Binder) error {
+			t.Fatal("overridden module must not be configured")
+			return nil
+		},
+	}
+	fake := &MockModule{
+		NameValue:     "fake-db",
+		ProducesValue: Keys(FooKey, BazKey),
+		ConfigureFunc: func(b Binder) error {
+			if err := FooKey.Put(b, 42); err != nil {
+				return err
+			}
+			return BazKey.Put(b, 7)
+		},
+	}
+	var got int
+	consumer := &MockModule{
+		NameValue:     "consumer",
+		ConsumesValue: Keys(FooKey),
+		ConfigureFunc: func(b Binder) error {
+			var err error
+			got, err = FooKey.Get(b)
+			return err
+		},
+	}
+	installer := &MockModule{
+		NameValue: "installer",
+		ConfigureFunc: func(b Binder) error {
+			return b.Install(db)
+		},
+	}
+	opts := []AssemblyOption{WithModuleOverride(ModuleID(db), fake)}
+	asm, err := NewAssemblyWithOptions(opts, consumer, installer)
+	require.NoError(t, err)
+	require.NoError(t, asm.Build())
+	require.Equal(t, 42, got)
+
+	ids := make([]string, 0)
+	for _, m := range asm.Graph().Modules {
+		ids = append(ids, m.ID)
+	}
+	require.Contains(t, ids, "github.com/goosz/modz:fake-db")
+	require.NotContains(t, ids, "github.com/goosz/modz:db")
+}
+
+func TestWithModuleOverride_Incompatible(t *testing.T) {
+	db := &MockModule{NameValue: "db", ProducesValue: Keys(FooKey, BarKey)}
+	fake := &MockModule{NameValue: "fake-db", ProducesValue: Keys(FooKey)}
+	opts := []AssemblyOption{WithModuleOverride(ModuleID(db), fake)}
+	asm, err := NewAssemblyWithOptions(opts, db)
+	require.Nil(t, asm)
+	require.ErrorIs(t, err, ErrIncompatibleOverride)
+	require.Contains(t, err.Error(), "replacement 'github.com/goosz/modz:fake-db' does not produce")
+}
+
+func TestWithModuleOverride_ReplacedModuleStaysInstalled(t *testing.T) {
+	db := &MockModule{NameValue: "db", ProducesValue: Keys(FooKey)}
+	fake := &MockModule{NameValue: "fake-db", ProducesValue: Keys(FooKey)}
+	opts := []AssemblyOption{WithModuleOverride(ModuleID(db), fake)}
+	asm, err := NewAssemblyWithOptions(opts, db, db)
+	require.Nil(t, asm)
+	require.ErrorIs(t, err, ErrDuplicateModule)
+}
+
+func TestWithModuleOverride_InvalidArguments(t *testing.T) {
+	m := &MockModule{NameValue: "m"}
+	_, err := NewAssemblyWithOptions([]AssemblyOption{WithModuleOverride("", m)})
+	require.Error(t, err)
+	_, err = NewAssemblyWithOptions([]AssemblyOption{WithModuleOverride("x:m", nil)})
+	require.Error(t, err)
+	_, err = NewAssemblyWithOptions([]AssemblyOption{WithModuleOverride("x:m", m), WithModuleOverride("x:m", m)})
+	require.ErrorContains(t, err, "already overridden")
+}
+
+func TestWithData_SkipsProducer(t *testing.T) {
+	child := &MockModule{NameValue: "child"}
+	producer := &MockModule{
+		NameValue:     "producer",
+		ProducesValue: Keys(FooKey),
+		ConsumesValue: Keys(QuxKey), // never produced, but the producer is skipped
+		ConfigureFunc: func(b Binder) error {
+			t.Fatal("seeded producer must not be configured")
+			return b.Install(child)
+		},
+	}
+	var got int
+	consumer := &MockModule{
+		NameValue:     "consumer",
+		ConsumesValue: Keys(FooKey),
+		ConfigureFunc: func(b Binder) error {
+			var err error
+			got, err = FooKey.Get(b)
+			return err
+		},
+	}
+	asm, err := NewAssemblyWithOptions([]AssemblyOption{WithData(FooKey, 5)}, producer, consumer)
+	require.NoError(t, err)
+	require.NoError(t, asm.Build())
+	require.Equal(t, 5, got)
+
+	val, err := FooKey.Get(asm)
+	require.NoError(t, err)
+	require.Equal(t, 5, val)
+}
+
+func TestWithData_PartiallySeededProducer(t *testing.T) {
+	producer := &MockModule{
+		NameValue:     "producer",
+		ProducesValue: Keys(FooKey, BarKey),
+		ConfigureFunc: func(b Binder) error {
+			if err := FooKey.Put(b, 1); err != nil {
+				return err
+			}
+			return BarKey.Put(b, 2)
+		},
+	}
+	asm, err := NewAssemblyWithOptions([]AssemblyOption{WithData(FooKey, 10)}, producer)
+	require.NoError(t, err)
+	require.NoError(t, asm.Build())
+
+	foo, err := FooKey.Get(asm)
+	require.NoError(t, err)
+	require.Equal(t, 10, foo)
+	bar, err := BarKey.Get(asm)
+	require.NoError(t, err)
+	require.Equal(t, 2, bar)
+}
+
+func TestWithData_WithoutProducer(t *testing.T) {
+	consumer := &MockModule{NameValue: "consumer", ConsumesValue: Keys(FooKey)}
+	asm, err := NewAssemblyWithOptions([]AssemblyOption{WithData(FooKey, 3)}, consumer)
+	require.NoError(t, err)
+	require.NoError(t, asm.Build())
+}
+
+func TestWithData_InvalidArguments(t *testing.T) {
+	_, err := NewAssemblyWithOptions([]AssemblyOption{WithData[int](nil, 1)})
+	require.Error(t, err)
+	_, err = NewAssemblyWithOptions([]AssemblyOption{WithData(FooKey, 1), WithData(FooKey, 2)})
+	require.ErrorContains(t, err, "already seeded")
+}
+
+func TestModuleID(t *testing.T) {
+	require.Equal(t, "github.com/goosz/modz:m", ModuleID(&MockModule{NameValue: "m"}))
+}
@@ -0,0 +1,127 @@
+package modz
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// BuildReport describes where the time of an [Assembly]'s Build() was spent.
+type BuildReport struct {
+	// Duration is the total time spent in Build.
+	Duration time.Duration
+	// Modules holds the report of every module whose configuration started, in the order in
+	// which it started.
+	Modules []ModuleReport
+	// Defaults lists the keys without a producer that were set to their default value (see
+	// [WithDefault]), sorted by signature.
+	Defaults []DataKey
+}
+
+// ModuleReport describes the configuration of a single module during Build().
+type ModuleReport struct {
+	// ModuleID is the signature of the module.
+	ModuleID string
+	// Wait is the time spent waiting for the module's consumed [Data], from the start of
+	// Build(), or from the module's installation if it was installed during Build().
+	Wait time.Duration
+	// Queue is the time spent waiting for a free worker once the module was ready; it is
+	// only significant with [WithParallelism].
+	Queue time.Duration
+	// Configure is the time spent in the module's Configure().
+	Configure time.Duration
+	// UnblockedBy is the key whose value made the module ready, or nil if the module was
+	// ready as soon as it was installed.
+	UnblockedBy DataKey
+	// Err is the error returned by the module's configuration, if any.
+	Err error
+}
+
+// BuildObserver is notified of the progress of an [Assembly]'s Build(), for example to
+// record it with a tracer. It is registered with [WithBuildObserver].
+//
+// With [WithParallelism], the methods concerning modules are called concurrently from
+// several goroutines.
+type BuildObserver interface {
+	// BuildStarted is called when Build() starts. The returned context, derived from ctx,
+	// becomes the context of the build, so that it can carry a tracing span.
+	BuildStarted(ctx context.Context) context.Context
+
+	// ModuleStarted is called before a module is configured. The returned context, derived
+	// from ctx, becomes the context the module observes through [Binder].Context().
+	ModuleStarted(ctx context.Context, moduleID string) context.Context
+
+	// ModuleFinished is called after a module is configured, successfully or not, with the
+	// context returned by ModuleStarted.
+	ModuleFinished(ctx context.Context, report ModuleReport)
+
+	// BuildFinished is called when Build() returns, with the context returned by
+	// BuildStarted, the complete report and the error returned by Build(), if any.
+	BuildFinished(ctx context.Context, report *BuildReport, err error)
+}
+
+// WithBuildObserver registers an observer notified of the progress of Build().
+//
+// Returns an error from [NewAssemblyWithOptions] if observer is nil.
+func WithBuildObserver(observer BuildObserver) AssemblyOption {
+	return func(o *assemblyOptions) error {
+		if observer == nil {
+			return fmt.Errorf("WithBuildObserver: observer must not be nil")
+		}
+		o.observer = observer
+		return nil
+	}
+}
+
+// moduleTiming records when a module went through each step of Build.
+type moduleTiming struct {
+	installed   time.Time
+	ready       time.Time
+	started     time.Time
+	finished    time.Time
+	unblockedBy DataKey
+	err         error
+}
+
+func (a *assembly) BuildReport() *BuildReport {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	if a.buildStarted.IsZero() {
+		return nil
+	}
+	report := &BuildReport{
+		Modules: make([]ModuleReport, 0, len(a.order)),
+	}
+	if !a.buildFinished.IsZero() {
+		report.Duration = a.buildFinished.Sub(a.buildStarted)
+	}
+	for _, b := range a.order {
+		report.Modules = append(report.Modules, a.moduleReport(b))
+	}
+	if len(a.defaulted) > 0 {
+		report.Defaults = sortedKeys(a.defaulted)
+	}
+	return report
+}
+
+// moduleReport returns the report of the module bound to b.
+//
+// The caller must hold a.mu.
+func (a *assembly) moduleReport(b *binder) ModuleReport {
+	t := b.timing
+	waitStart := t.installed
+	if waitStart.Before(a.buildStarted) {
+		waitStart = a.buildStarted
+	}
+	report := ModuleReport{
+		ModuleID:    b.moduleSignature.String(),
+		Wait:        t.ready.Sub(waitStart),
+		Queue:       t.started.Sub(t.ready),
+		UnblockedBy: t.unblockedBy,
+		Err:         t.err,
+	}
+	if !t.finished.IsZero() {
+		report.Configure = t.finished.Sub(t.started)
+	}
+	return report
+}
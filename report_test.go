@@ -0,0 +1,123 @@
+package modz
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAssembly_BuildReport(t *testing.T) {
+	producer := &MockModule{
+		NameValue:     "producer",
+		ProducesValue: Keys(FooKey),
+		ConfigureFunc: func(b Binder) error {
+			time.Sleep(20 * time.Millisecond)
+			return FooKey.Put(b, 1)
+		},
+	}
+	consumer := &MockModule{NameValue: "consumer", ConsumesValue: Keys(FooKey)}
+	asm, err := NewAssembly(consumer, producer)
+	require.NoError(t, err)
+	require.Nil(t, asm.BuildReport())
+	require.NoError(t, asm.Build())
+
+	report := asm.BuildReport()
+	require.NotNil(t, report)
+	require.Len(t, report.Modules, 2)
+
+	p := report.Modules[0]
+	require.Equal(t, "github.com/goosz/modz:producer", p.ModuleID)
+	require.Nil(t, p.UnblockedBy)
+	require.GreaterOrEqual(t, p.Configure, 20*time.Millisecond)
+	require.NoError(t, p.Err)
+
+	c := report.Modules[1]
+	require.Equal(t, "github.com/goosz/modz:consumer", c.ModuleID)
+	require.Equal(t, FooKey, c.UnblockedBy)
+	require.GreaterOrEqual(t, c.Wait, 20*time.Millisecond)
+	require.GreaterOrEqual(t, report.Duration, p.Configure+c.Configure)
+}
+
+func TestAssembly_BuildReport_Failure(t *testing.T) {
+	failing := &MockModule{
+		NameValue:     "failing",
+		ConfigureFunc: func(b Binder) error { return errors.New("failure") },
+	}
+	asm, err := NewAssembly(failing)
+	require.NoError(t, err)
+	require.Error(t, asm.Build())
+
+	report := asm.BuildReport()
+	require.Len(t, report.Modules, 1)
+	require.ErrorContains(t, report.Modules[0].Err, "failure")
+}
+
+type contextKey struct{}
+
+// recordingObserver is a BuildObserver recording the events it receives.
+type recordingObserver struct {
+	mu     sync.Mutex
+	events []string
+	report *BuildReport
+	err    error
+}
+
+func (o *recordingObserver) record(event string) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.events = append(o.events, event)
+}
+
+func (o *recordingObserver) BuildStarted(ctx context.Context) context.Context {
+	o.record("build started")
+	return context.WithValue(ctx, contextKey{}, "build")
+}
+
+func (o *recordingObserver) ModuleStarted(ctx context.Context, moduleID string) context.Context {
+	o.record("started " + moduleID + " in " + ctx.Value(contextKey{}).(string))
+	return context.WithValue(ctx, contextKey{}, moduleID)
+}
+
+func (o *recordingObserver) ModuleFinished(ctx context.Context, report ModuleReport) {
+	o.record("finished " + report.ModuleID + " in " + ctx.Value(contextKey{}).(string))
+}
+
+func (o *recordingObserver) BuildFinished(ctx context.Context, report *BuildReport, err error) {
+	o.record("build finished in " + ctx.Value(contextKey{}).(string))
+	o.report = report
+	o.err = err
+}
+
+func TestWithBuildObserver(t *testing.T) {
+	observer := &recordingObserver{}
+	var moduleCtxValue any
+	m := &MockModule{
+		NameValue: "m",
+		ConfigureFunc: func(b Binder) error {
+			moduleCtxValue = b.Context().Value(contextKey{})
+			return nil
+		},
+	}
+	asm, err := NewAssemblyWithOptions([]AssemblyOption{WithBuildObserver(observer)}, m)
+	require.NoError(t, err)
+	require.NoError(t, asm.Build())
+
+	require.Equal(t, []string{
+		"build started",
+		"started github.com/goosz/modz:m in build",
+		"finished github.com/goosz/modz:m in github.com/goosz/modz:m",
+		"build finished in build",
+	}, observer.events)
+	require.Equal(t, "github.com/goosz/modz:m", moduleCtxValue)
+	require.NoError(t, observer.err)
+	require.Len(t, observer.report.Modules, 1)
+}
+
+func TestWithBuildObserver_Nil(t *testing.T) {
+	_, err := NewAssemblyWithOptions([]AssemblyOption{WithBuildObserver(nil)})
+	require.Error(t, err)
+}
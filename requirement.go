@@ -0,0 +1,142 @@
+package modz
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/goosz/commonz"
+)
+
+// Requirer is implemented by modules that must be configured after other modules although
+// no [Data] flows between them, such as a module seeding a database after the module
+// applying its migrations.
+//
+// During the module's discovery phase, the [Assembly] calls Requires() in addition to
+// Consumes(). The returned modules only identify the required modules by their signature, as
+// returned by [ModuleID]; they are not installed. The module is configured once every
+// required module has been configured successfully, whether it is installed in the same
+// assembly, in place of a module override (see [WithModuleOverride]), or in a parent
+// assembly (see [WithParent]). A required module that is never installed is reported as a
+// [MissingProducerError], and circular requirements as a [CycleError]. If a required module
+// fails, the module is not configured. Like Consumes(), Requires() must be deterministic.
+//
+// To be configured after whichever modules, if any, perform a step, a module can instead
+// consume a [Marker] key declared in Produces() by each of them.
+type Requirer interface {
+	Module
+
+	// Requires returns the modules that must be configured before this module.
+	Requires() []Module
+}
+
+// Marker is a [DataKey] without a value, which orders modules without any [Data] flowing
+// between them.
+//
+// Any number of modules may declare a Marker key in Produces(), like a [SetData] key, and
+// nothing needs to be stored under it: a producing module marks the key by being configured
+// successfully. Modules that declare the key in Consumes() are configured only after every
+// producing module has been configured, or right away if no module produces it. For example,
+// a module seeding a database can consume a "migrated" marker produced by every module
+// applying migrations.
+//
+// Always use [NewMarker] to create new Marker keys.
+type Marker interface {
+	DataKey
+
+	marker()
+}
+
+// markerKey is the concrete implementation of the Marker interface. It is a collection key
+// to which its producers contribute nothing: its value is assembled once they have all been
+// configured.
+type markerKey struct {
+	dataKeySignature dataKeySignature
+	serial           uint64
+}
+
+// Ensure that *markerKey implements Marker and collectionKey.
+var _ Marker = (*markerKey)(nil)
+var _ collectionKey = (*markerKey)(nil)
+
+func (d *markerKey) marker() {}
+
+func (d *markerKey) checkContribution(_ []any, _ any) error {
+	return newDataOperationError(ErrInvalidArgument, d, "a marker has no value")
+}
+
+func (d *markerKey) aggregate(_ []any) any {
+	return struct{}{}
+}
+
+func (d *markerKey) signature() dataKeySignature {
+	return d.dataKeySignature
+}
+
+// typeName returns the name of the Go type stored under this key.
+func (d *markerKey) typeName() string {
+	return commonz.TypeName(reflect.TypeFor[struct{}]())
+}
+
+func (d *markerKey) String() string {
+	return fmt.Sprintf("Marker(%s#%d)", d.signature(), d.serial)
+}
+
+// NewMarker creates a new [Marker] key ordering the modules producing it before the modules
+// consuming it.
+//
+// Like [NewData], it must be called from package-level var declarations only, and it panics
+// otherwise.
+func NewMarker(name string) Marker {
+	sig, serial := newDataKeyIdentity("NewMarker", name)
+	return &markerKey{
+		dataKeySignature: sig,
+		serial:           serial,
+	}
+}
+
+// moduleKey is the key a module requiring another one waits for, see [Requirer]. It is
+// never stored: the waiting modules are notified once the required module has been
+// configured.
+type moduleKey struct {
+	sig moduleSignature
+}
+
+func (k moduleKey) signature() dataKeySignature {
+	return dataKeySignature{name: k.sig.name, pkg: k.sig.packageName}
+}
+
+func (k moduleKey) String() string {
+	return fmt.Sprintf("Module(%s)", k.sig)
+}
+
+// resolveRequirements notifies the modules requiring a module that has been configured
+// successfully, and returns as soon as one of them becomes ready. It must only be called when
+// no module is being configured.
+//
+// Returns true if any module became ready.
+//
+// The caller must hold a.mu.
+func (a *assembly) resolveRequirements() bool {
+	for _, k := range sortedKeys(a.waiters) {
+		mk, ok := k.(moduleKey)
+		if !ok {
+			continue
+		}
+		b, installed := a.bindings[mk.sig]
+		if !installed || !b.configured.Load() || b.failed.Load() {
+			continue
+		}
+		progress := false
+		for _, w := range a.waiters[k] {
+			if w.resolveDependency(k) {
+				a.schedule(w)
+				progress = true
+			}
+		}
+		delete(a.waiters, k)
+		if progress {
+			return true
+		}
+	}
+	return false
+}
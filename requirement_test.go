@@ -0,0 +1,244 @@
+package modz
+
+import (
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// orderRecorder records the order in which modules are configured.
+type orderRecorder struct {
+	mu    sync.Mutex
+	names []string
+}
+
+func (r *orderRecorder) record(name string) func(Binder) error {
+	return func(Binder) error {
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		r.names = append(r.names, name)
+		return nil
+	}
+}
+
+// newRequirer returns a module named name requiring the given modules.
+func newRequirer(r *orderRecorder, name string, required ...Module) *MockRequirerModule {
+	return &MockRequirerModule{
+		MockModule:    MockModule{NameValue: name, ConfigureFunc: r.record(name)},
+		RequiresValue: required,
+	}
+}
+
+func TestRequirer_Order(t *testing.T) {
+	for _, parallelism := range []int{1, 4} {
+		r := &orderRecorder{}
+		migrations := &MockModule{NameValue: "migrations", ConfigureFunc: r.record("migrations")}
+		seed := newRequirer(r, "seed", &MockModule{NameValue: "migrations"})
+		asm, err := NewAssemblyWithOptions([]AssemblyOption{WithParallelism(parallelism)}, seed, migrations)
+		require.NoError(t, err)
+		require.NoError(t, asm.Build())
+		require.Equal(t, []string{"migrations", "seed"}, r.names)
+	}
+}
+
+func TestRequirer_InstalledLater(t *testing.T) {
+	r := &orderRecorder{}
+	seed := newRequirer(r, "seed", &MockModule{NameValue: "migrations"})
+	installer := &MockModule{
+		NameValue:     "installer",
+		ProducesValue: Keys(FooKey),
+		ConfigureFunc: func(b Binder) error {
+			if err := b.Install(&MockModule{NameValue: "migrations", ConfigureFunc: r.record("migrations")}); err != nil {
+				return err
+			}
+			return FooKey.Put(b, 1)
+		},
+	}
+	// The required module is installed only once bar has been configured.
+	installer.ConsumesValue = Keys(BarKey)
+	barProducer := &MockModule{
+		NameValue:     "bar",
+		ProducesValue: Keys(BarKey),
+		ConfigureFunc: func(b Binder) error { return BarKey.Put(b, 1) },
+	}
+	asm, err := NewAssembly(seed, installer, barProducer)
+	require.NoError(t, err)
+	require.NoError(t, asm.Build())
+	require.Equal(t, []string{"migrations", "seed"}, r.names)
+}
+
+func TestRequirer_Missing(t *testing.T) {
+	r := &orderRecorder{}
+	asm, err := NewAssembly(newRequirer(r, "seed", &MockModule{NameValue: "migrations"}))
+	require.NoError(t, err)
+
+	err = asm.Build()
+	require.ErrorIs(t, err, ErrMissingProducer)
+	var missingErr *MissingProducerError
+	require.ErrorAs(t, err, &missingErr)
+	require.Equal(t, []string{"github.com/goosz/modz:seed"}, missingErr.ModuleIDs)
+	require.EqualError(t, missingErr, "module 'github.com/goosz/modz:migrations': not installed (required by 'github.com/goosz/modz:seed')")
+	require.Empty(t, r.names)
+}
+
+func TestRequirer_Failure(t *testing.T) {
+	r := &orderRecorder{}
+	errBoom := errors.New("boom")
+	migrations := &MockModule{NameValue: "migrations", ConfigureFunc: func(Binder) error { return errBoom }}
+	seed := newRequirer(r, "seed", &MockModule{NameValue: "migrations"})
+	asm, err := NewAssemblyWithOptions([]AssemblyOption{WithAggregateErrors()}, seed, migrations)
+	require.NoError(t, err)
+
+	err = asm.Build()
+	require.ErrorIs(t, err, errBoom)
+	var asmErr *AssemblyError
+	require.ErrorAs(t, err, &asmErr)
+	require.Equal(t, []string{"github.com/goosz/modz:seed"}, asmErr.Skipped)
+	require.Empty(t, r.names)
+}
+
+func TestRequirer_Cycle(t *testing.T) {
+	r := &orderRecorder{}
+	a := newRequirer(r, "a", &MockModule{NameValue: "b"})
+	b := &MockModule{NameValue: "b", ConsumesValue: Keys(FooKey)}
+	a.ProducesValue = Keys(FooKey)
+	asm, err := NewAssembly(a, b)
+	require.NoError(t, err)
+
+	err = asm.Build()
+	var cycleErr *CycleError
+	require.ErrorAs(t, err, &cycleErr)
+	require.Len(t, cycleErr.Cycle, 2)
+	require.Equal(t, "github.com/goosz/modz:a", cycleErr.Cycle[0].ModuleID)
+	require.Equal(t, "github.com/goosz/modz:b", cycleErr.Cycle[0].ProducerID)
+	require.Equal(t, FooKey, cycleErr.Cycle[1].Key)
+	require.ErrorContains(t, err, "module 'github.com/goosz/modz:a' requires module 'github.com/goosz/modz:b', which consumes")
+
+	// A module requiring itself waits for itself.
+	asm, err = NewAssembly(newRequirer(r, "self", &MockModule{NameValue: "self"}))
+	require.NoError(t, err)
+	require.ErrorIs(t, asm.Build(), ErrCycle)
+}
+
+func TestRequirer_Override(t *testing.T) {
+	r := &orderRecorder{}
+	seed := newRequirer(r, "seed", &MockModule{NameValue: "migrations"})
+	fake := &MockModule{NameValue: "fake-migrations", ConfigureFunc: r.record("fake-migrations")}
+	asm, err := NewAssemblyWithOptions(
+		[]AssemblyOption{WithModuleOverride("github.com/goosz/modz:migrations", fake)},
+		seed, &MockModule{NameValue: "migrations"})
+	require.NoError(t, err)
+	require.NoError(t, asm.Build())
+	require.Equal(t, []string{"fake-migrations", "seed"}, r.names)
+}
+
+func TestRequirer_FromParent(t *testing.T) {
+	r := &orderRecorder{}
+	parent, err := NewAssembly(&MockModule{NameValue: "migrations", ConfigureFunc: r.record("migrations")})
+	require.NoError(t, err)
+	require.NoError(t, parent.Build())
+
+	child, err := NewAssemblyWithOptions([]AssemblyOption{WithParent(parent)},
+		newRequirer(r, "seed", &MockModule{NameValue: "migrations"}))
+	require.NoError(t, err)
+	require.NoError(t, child.Build())
+	require.Equal(t, []string{"migrations", "seed"}, r.names)
+}
+
+func TestRequirer_NilModule(t *testing.T) {
+	r := &orderRecorder{}
+	_, err := NewAssembly(newRequirer(r, "seed", nil))
+	require.ErrorIs(t, err, ErrInvalidArgument)
+}
+
+func TestRequirer_NoSignatureClash(t *testing.T) {
+	// A module may share its name with a data key of its package.
+	r := &orderRecorder{}
+	produced := &MockModule{
+		NameValue:     "produced",
+		ProducesValue: Keys(ProducedKey),
+		ConfigureFunc: func(b Binder) error { return ProducedKey.Put(b, "x") },
+	}
+	asm, err := NewAssembly(newRequirer(r, "seed", &MockModule{NameValue: "produced"}), produced)
+	require.NoError(t, err)
+	require.NoError(t, asm.Build())
+	require.Equal(t, []string{"seed"}, r.names)
+}
+
+func TestRequirer_Graph(t *testing.T) {
+	r := &orderRecorder{}
+	asm, err := NewAssembly(newRequirer(r, "seed", &MockModule{NameValue: "migrations"}))
+	require.NoError(t, err)
+
+	g := asm.Graph()
+	require.Equal(t, []GraphModule{{
+		ID:       "github.com/goosz/modz:seed",
+		Requires: []string{"github.com/goosz/modz:migrations"},
+	}}, g.Modules)
+
+	var sb strings.Builder
+	require.NoError(t, g.WriteDOT(&sb))
+	require.Contains(t, sb.String(), `"module:github.com/goosz/modz:migrations" -> "module:github.com/goosz/modz:seed" [label="requires", style=dotted];`)
+}
+
+func TestMarker(t *testing.T) {
+	for _, parallelism := range []int{1, 4} {
+		r := &orderRecorder{}
+		newMigration := func(name string) *MockModule {
+			return &MockModule{NameValue: name, ProducesValue: Keys(MigratedKey), ConfigureFunc: r.record(name)}
+		}
+		seed := &MockModule{NameValue: "seed", ConsumesValue: Keys(MigratedKey), ConfigureFunc: r.record("seed")}
+		asm, err := NewAssemblyWithOptions([]AssemblyOption{WithParallelism(parallelism)},
+			seed, newMigration("users"), newMigration("orders"))
+		require.NoError(t, err)
+		require.NoError(t, asm.Build())
+		require.Len(t, r.names, 3)
+		require.Equal(t, "seed", r.names[2])
+	}
+}
+
+func TestMarker_NoProducer(t *testing.T) {
+	r := &orderRecorder{}
+	seed := &MockModule{NameValue: "seed", ConsumesValue: Keys(MigratedKey), ConfigureFunc: r.record("seed")}
+	asm, err := NewAssembly(seed)
+	require.NoError(t, err)
+	require.NoError(t, asm.Build())
+	require.Equal(t, []string{"seed"}, r.names)
+}
+
+func TestMarker_ProducerFailure(t *testing.T) {
+	r := &orderRecorder{}
+	errBoom := errors.New("boom")
+	migration := &MockModule{
+		NameValue:     "migration",
+		ProducesValue: Keys(MigratedKey),
+		ConfigureFunc: func(Binder) error { return errBoom },
+	}
+	seed := &MockModule{NameValue: "seed", ConsumesValue: Keys(MigratedKey), ConfigureFunc: r.record("seed")}
+	asm, err := NewAssemblyWithOptions([]AssemblyOption{WithAggregateErrors()}, seed, migration)
+	require.NoError(t, err)
+
+	err = asm.Build()
+	require.ErrorIs(t, err, errBoom)
+	var asmErr *AssemblyError
+	require.ErrorAs(t, err, &asmErr)
+	require.Equal(t, []string{"github.com/goosz/modz:seed"}, asmErr.Skipped)
+	require.Empty(t, r.names)
+}
+
+func TestMarker_Graph(t *testing.T) {
+	seed := &MockModule{NameValue: "seed", ConsumesValue: Keys(MigratedKey)}
+	asm, err := NewAssembly(seed)
+	require.NoError(t, err)
+
+	g := asm.Graph()
+	require.Equal(t, []GraphKey{{
+		ID:         "github.com/goosz/modz:migrated",
+		Type:       "struct {}",
+		Label:      MigratedKey.(*markerKey).String(),
+		Collection: true,
+	}}, g.Keys)
+}
@@ -0,0 +1,117 @@
+package modz
+
+import (
+	"sync"
+)
+
+// DataStore is a map-backed [DataReader] and [DataWriter], holding values under [Data] keys
+// outside of an [Assembly].
+//
+// A DataStore lets data keys be used as typed keys wherever values are passed around: as test
+// fixtures, request-scoped bags, or configuration snapshots. Values are stored with Put() or
+// Provide() on a [Data] key and read with Get() or Lookup(), as with a [Binder]:
+//
+//	store := modz.NewDataStore()
+//	if err := UserKey.Put(store, user); err != nil {
+//		return err
+//	}
+//	user, err := UserKey.Get(store)
+//
+// Like an Assembly, a DataStore holds at most one value per key: storing a second value
+// under the same key fails with [ErrAlreadySet], and reading a key without a value fails with
+// [ErrNotProduced]. Values stored with Provide() are computed on the first Get(), and an
+// error from the constructor is returned as is. Elements added to a [SetData] or [MapData]
+// key are collected in the order they are added; reading a collection key without any
+// element returns an empty collection.
+//
+// There are no declarations to enforce: any key can be read or written. A DataStore is safe
+// for concurrent use. The zero value is not usable; create DataStores with [NewDataStore].
+type DataStore struct {
+	mu            sync.RWMutex
+	data          map[DataKey]any
+	contributions map[DataKey][]any
+}
+
+// Ensure that *DataStore implements DataReader and DataWriter.
+var _ DataReader = (*DataStore)(nil)
+var _ DataWriter = (*DataStore)(nil)
+
+// NewDataStore creates an empty [DataStore].
+func NewDataStore() *DataStore {
+	return &DataStore{
+		data:          make(map[DataKey]any),
+		contributions: make(map[DataKey][]any),
+	}
+}
+
+// Keys returns the keys holding a value in the store, sorted by signature. Collection keys
+// are included once an element has been added to them.
+func (s *DataStore) Keys() []DataKey {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	keys := make(map[DataKey]struct{}, len(s.data)+len(s.contributions))
+	for k := range s.data {
+		keys[k] = struct{}{}
+	}
+	for k := range s.contributions {
+		keys[k] = struct{}{}
+	}
+	return sortedKeys(keys)
+}
+
+// Has reports whether key holds a value in the store, without computing a value stored with
+// Provide().
+func (s *DataStore) Has(key DataKey) bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if _, ok := s.data[key]; ok {
+		return true
+	}
+	_, ok := s.contributions[key]
+	return ok
+}
+
+func (s *DataStore) getData(key DataKey) (any, error) {
+	if key == nil {
+		return nil, newDataOperationError(ErrInvalidArgument, nil, "cannot get data with nil key")
+	}
+	s.mu.RLock()
+	val, ok := s.data[key]
+	contributions := s.contributions[key]
+	s.mu.RUnlock()
+	if ck, isCollection := key.(collectionKey); isCollection {
+		return ck.aggregate(contributions), nil
+	}
+	if !ok {
+		return nil, newDataOperationError(ErrNotProduced, key, "no value found")
+	}
+	if lv, ok := val.(*lazyValue); ok {
+		return lv.get()
+	}
+	return val, nil
+}
+
+func (s *DataStore) putData(key DataKey, value any) error {
+	if key == nil {
+		return newDataOperationError(ErrInvalidArgument, nil, "cannot put data with nil key")
+	}
+	if vk, ok := key.(validatedKey); ok {
+		if err := vk.validate(value); err != nil {
+			return err
+		}
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if ck, ok := key.(collectionKey); ok {
+		if err := ck.checkContribution(s.contributions[key], value); err != nil {
+			return err
+		}
+		s.contributions[key] = append(s.contributions[key], value)
+		return nil
+	}
+	if _, exists := s.data[key]; exists {
+		return newDataOperationError(ErrAlreadySet, key, "already set")
+	}
+	s.data[key] = value
+	return nil
+}
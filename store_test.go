@@ -0,0 +1,136 @@
+package modz
+
+import (
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDataStore_PutGet(t *testing.T) {
+	store := NewDataStore()
+	require.NoError(t, FooKey.Put(store, 42))
+	require.NoError(t, ProducedKey.Put(store, "hello"))
+
+	foo, err := FooKey.Get(store)
+	require.NoError(t, err)
+	require.Equal(t, 42, foo)
+	produced, err := ProducedKey.Get(store)
+	require.NoError(t, err)
+	require.Equal(t, "hello", produced)
+
+	require.True(t, store.Has(FooKey))
+	require.False(t, store.Has(BarKey))
+	require.Equal(t, []DataKey{FooKey, ProducedKey}, store.Keys())
+}
+
+func TestDataStore_Missing(t *testing.T) {
+	store := NewDataStore()
+
+	_, err := FooKey.Get(store)
+	require.ErrorIs(t, err, ErrNotProduced)
+
+	val, ok, err := FooKey.Lookup(store)
+	require.NoError(t, err)
+	require.False(t, ok)
+	require.Zero(t, val)
+}
+
+func TestDataStore_AlreadySet(t *testing.T) {
+	store := NewDataStore()
+	require.NoError(t, FooKey.Put(store, 1))
+
+	err := FooKey.Put(store, 2)
+	require.ErrorIs(t, err, ErrAlreadySet)
+	foo, err := FooKey.Get(store)
+	require.NoError(t, err)
+	require.Equal(t, 1, foo)
+}
+
+func TestDataStore_NilKey(t *testing.T) {
+	store := NewDataStore()
+
+	_, err := store.getData(nil)
+	require.ErrorIs(t, err, ErrInvalidArgument)
+	err = store.putData(nil, 1)
+	require.ErrorIs(t, err, ErrInvalidArgument)
+}
+
+func TestDataStore_TypeMismatch(t *testing.T) {
+	store := NewDataStore()
+	require.NoError(t, store.putData(FooKey, "not an int"))
+
+	_, err := FooKey.Get(store)
+	require.ErrorIs(t, err, ErrTypeMismatch)
+}
+
+func TestDataStore_Provide(t *testing.T) {
+	store := NewDataStore()
+	calls := 0
+	require.NoError(t, FooKey.Provide(store, func() (int, error) {
+		calls++
+		return 7, nil
+	}))
+	require.True(t, store.Has(FooKey))
+	require.Equal(t, 0, calls)
+
+	for range 2 {
+		foo, err := FooKey.Get(store)
+		require.NoError(t, err)
+		require.Equal(t, 7, foo)
+	}
+	require.Equal(t, 1, calls)
+}
+
+func TestDataStore_ProvideError(t *testing.T) {
+	store := NewDataStore()
+	errBoom := errors.New("boom")
+	require.NoError(t, FooKey.Provide(store, func() (int, error) {
+		return 0, errBoom
+	}))
+
+	_, err := FooKey.Get(store)
+	require.Equal(t, errBoom, err)
+}
+
+func TestDataStore_Collections(t *testing.T) {
+	store := NewDataStore()
+
+	list, err := ListKey.Get(store)
+	require.NoError(t, err)
+	require.Empty(t, list)
+	require.False(t, store.Has(ListKey))
+
+	require.NoError(t, ListKey.Add(store, "b"))
+	require.NoError(t, ListKey.Add(store, "a"))
+	require.NoError(t, IndexKey.Put(store, "x", 1))
+	err = IndexKey.Put(store, "x", 2)
+	require.ErrorIs(t, err, ErrAlreadySet)
+
+	list, err = ListKey.Get(store)
+	require.NoError(t, err)
+	require.Equal(t, []string{"b", "a"}, list)
+	index, err := IndexKey.Get(store)
+	require.NoError(t, err)
+	require.Equal(t, map[string]int{"x": 1}, index)
+	require.Equal(t, []DataKey{IndexKey, ListKey}, store.Keys())
+}
+
+func TestDataStore_Concurrent(t *testing.T) {
+	store := NewDataStore()
+	var wg sync.WaitGroup
+	for range 10 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			require.NoError(t, ListKey.Add(store, "x"))
+			_, _, _ = FooKey.Lookup(store)
+		}()
+	}
+	wg.Wait()
+
+	list, err := ListKey.Get(store)
+	require.NoError(t, err)
+	require.Len(t, list, 10)
+}